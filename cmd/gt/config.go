@@ -0,0 +1,51 @@
+package main
+
+import (
+	"github.com/schwarzit/go-template/pkg/gotemplate"
+	"github.com/spf13/cobra"
+)
+
+func buildConfigCommand(gt *gotemplate.GT) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the defaults of options",
+		Long: `Manage the defaults of options in the user's config file "gt/config.yml" in $XDG_CONFIG_HOME.
+
+An organisation can share defaults in a config file with the same format that is set in $GT_ORG_CONFIG.
+The user's defaults take precedence over the organisation's, both replace the defaults of "gt new".
+
+Options are referenced by their name for base options and by "<category>.<name>" for extensions, e.g. "ci.provider".
+Values of options with choices can also be the description of a choice, e.g. "gitlab".
+Strings can be templates using the values of earlier options, e.g. "gitlab.company.com/team/{{ .Base.projectSlug }}".`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get [option]",
+		Short: "Print the default of an option or all defaults together with their config file",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var key string
+			if len(args) > 0 {
+				key = args[0]
+			}
+
+			return gt.PrintConfig(key)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <option> <value>",
+		Short: "Set the default of an option in the user's config file",
+		Args:  cobra.ExactArgs(2), //nolint:gomnd // option and value
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := gotemplate.UserConfigFile()
+			if err != nil {
+				return err
+			}
+
+			return gt.SetConfigDefault(file, args[0], args[1])
+		},
+	})
+
+	return cmd
+}
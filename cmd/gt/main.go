@@ -63,8 +63,13 @@ For more information, please visit the project's Github page: github.com/schwarz
 		SilenceUsage:  true,
 	}
 
+	cmd.PersistentFlags().BoolVarP(&gt.Verbose, "verbose", "v", false, "Stream the output of all executed commands and report why defaults fell back to static values")
+
 	cmd.AddCommand(buildNewCommand(output, gt))
 	cmd.AddCommand(buildVersionCommand(output, gt))
+	cmd.AddCommand(buildTemplateCommand(gt))
+	cmd.AddCommand(buildPresetsCommand(gt))
+	cmd.AddCommand(buildConfigCommand(gt))
 
 	return cmd
 }
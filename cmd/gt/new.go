@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/muesli/termenv"
 	"github.com/schwarzit/go-template/pkg/gotemplate"
@@ -11,6 +14,7 @@ import (
 func buildNewCommand(output *termenv.Output, gt *gotemplate.GT) *cobra.Command {
 	var (
 		configFile string
+		preset     string
 		opts       gotemplate.NewRepositoryOptions
 	)
 
@@ -42,6 +46,16 @@ To get further information look at the flag's documentation.
 				return err
 			}
 
+			if err := gt.ApplyConfig(); err != nil {
+				return err
+			}
+
+			if preset != "" {
+				if err := gt.SelectPreset(preset); err != nil {
+					return err
+				}
+			}
+
 			configValues, err := getValues(gt, configFile)
 			if err != nil {
 				return err
@@ -51,7 +65,11 @@ To get further information look at the flag's documentation.
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return gt.InitNewProject(&opts)
+			// only handle signals while generating so that ^C still quits the interactive mode right away
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return gt.InitNewProject(ctx, &opts)
 		},
 	}
 
@@ -60,6 +78,7 @@ To get further information look at the flag's documentation.
 		"config", "c", "",
 		`YAML file that defines all parameters.
 This is helpful if you don't want to run the CLI interactively.
+Parameters that are left out get their default value like in interactive mode (e.g. "projectSlug" is derived from "projectName").
 An example file could look like (other example can be found here:
 https://github.com/SchwarzIT/go-template/blob/main/pkg/gotemplate/testdata/values.yml):
 
@@ -76,12 +95,46 @@ extensions:
     grpcGateway: false`,
 	)
 
+	cmd.Flags().StringVar(
+		&preset,
+		"preset", "",
+		`Name of a preset whose values are set before the remaining ones are asked for or read from the config file.
+Values in the config file take precedence over the preset's. Run "gt presets list" to see all presets.
+`)
+
+	cmd.Flags().BoolVar(
+		&gt.Strict,
+		"strict", false,
+		`Fail if the config file contains keys that don't belong to any option instead of ignoring them with a warning.
+`)
+
 	cmd.Flags().StringVarP(
 		&opts.OutputDir,
 		"outputDir", "o", "./",
 		`Output directory for the newly created project folder.
 `)
 
+	cmd.Flags().BoolVar(
+		&opts.InPlace,
+		"in-place", false,
+		`Generate the project directly into the output directory instead of a new folder named after the projectSlug.
+The output directory may exist if it is empty or only contains a ".git" folder (e.g. a freshly cloned repo).
+`)
+
+	cmd.Flags().BoolVar(
+		&opts.Merge,
+		"merge", false,
+		`Allow generating into an existing directory.
+Generated files that already exist with different content are handled according to "--on-conflict".
+`)
+
+	cmd.Flags().StringVar(
+		(*string)(&opts.OnConflict),
+		"on-conflict", string(gotemplate.ConflictSideFile),
+		fmt.Sprintf(`How to handle existing files in merge mode, one of %v.
+"skip" keeps the existing file, "overwrite" replaces it and "side-file" writes the generated file next to it with a ".gt-new" suffix.
+`, gotemplate.ConflictPolicies()))
+
 	return cmd
 }
 
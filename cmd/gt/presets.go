@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/schwarzit/go-template/pkg/gotemplate"
+	"github.com/spf13/cobra"
+)
+
+func buildPresetsCommand(gt *gotemplate.GT) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "presets",
+		Short: "Work with presets for common project shapes",
+		Long: `Presets are named bundles of option values for common project shapes that can be used with "gt new --preset <name>".
+
+Besides the built-in presets every "<name>.yml" file in the directory set in $GT_PRESETS_DIR
+and in "gt/presets" in the user's config directory ($XDG_CONFIG_HOME) is a preset.
+Those files have the same format as config files with an additional "description".
+User presets replace organisation presets, which replace built-in presets with the same name.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List all presets and the options they set",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return gt.PrintPresets()
+		},
+	})
+
+	return cmd
+}
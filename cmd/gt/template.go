@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	"github.com/schwarzit/go-template/pkg/gotemplate"
+	"github.com/spf13/cobra"
+)
+
+func buildTemplateCommand(gt *gotemplate.GT) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Work on the template gt generates projects from",
+	}
+
+	cmd.AddCommand(buildTemplateCheckCommand(gt))
+
+	return cmd
+}
+
+func buildTemplateCheckCommand(gt *gotemplate.GT) *cobra.Command {
+	var (
+		templateDir string
+		opts        gotemplate.CheckTemplateOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Generate and check a project for every option combination",
+		Long: `Generate a project for every combination of the extension options and check that it is valid.
+
+Every option that is restricted to choices takes each of them and every bool option takes both values.
+Options that would not be displayed in interactive mode for a combination keep their default.
+By default the values of all categories are combined with each other,
+use "--reduced" to only vary one category at a time while all others keep their defaults.
+
+The combinations that fail any of the checks are reported together with the failures.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if templateDir != "" {
+				if _, err := os.Stat(templateDir); err != nil {
+					return err
+				}
+
+				gt.Template = os.DirFS(templateDir)
+			}
+
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return gt.CheckTemplate(ctx, &opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(
+		&opts.Checks,
+		"checks", gotemplate.TemplateChecks(),
+		fmt.Sprintf(`Checks to run on every generated project, any of %v.
+"leftover" finds unrendered template actions, "yaml" and "makefile" check the syntax of those files
+and "build" and "vet" run the respective go command.
+`, gotemplate.TemplateChecks()))
+
+	cmd.Flags().BoolVar(
+		&opts.Reduced,
+		"reduced", false,
+		`Vary one category at a time instead of combining the values of all categories with each other.
+This is faster but misses failures that only occur for combinations of categories.
+`)
+
+	cmd.Flags().IntVar(
+		&opts.Parallel,
+		"parallel", runtime.NumCPU(),
+		`Number of combinations that are generated and checked at the same time.
+`)
+
+	cmd.Flags().StringVar(
+		&templateDir,
+		"template", "",
+		`Directory of a custom template to check instead of the embedded one.
+The directory has the same layout as the "_template" folder in github.com/schwarzit/go-template.
+`)
+
+	return cmd
+}
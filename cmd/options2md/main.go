@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"errors"
 	"flag"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"text/template"
 
 	"github.com/schwarzit/go-template/pkg/gotemplate"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -21,44 +23,149 @@ The options are divided into base options and extension options.
 Base options are needed for the minimal base template and are mandatory in any case.
 The extension options on the other hand enable optional features in the template such as gRPC support or open source lincenses.
 
-## Base
+Options of extensions are referenced by ` + "`<category>.<name>`" + `, e.g. in the conditions deciding whether an option is shown.
+Hidden options are set to their default.
 
-| Name | Description |
-| :--- | :---------- |
-{{- range $index, $option := .Base}}
-| {{ $option.Name | code }} | {{ $option.Description | replace "\n" "<br>" }} |
-{{- end}}
+## Base
+{{ template "options" (options "" .Base) }}
 
 ## Extensions
 {{- range $index, $category := .Extensions}}
 
 ### {{ $category.Name | code }}
-
-| Name | Description |
-| :--- | :---------- |
-{{- range $index, $option := $category.Options}}
-| {{ $option.Name | code }} | {{ $option.Description | replace "\n" "<br>" }} |
+{{ template "options" (options $category.Name $category.Options) }}
 {{- end}}
+
+## Example values file
+
+The following values file sets every option to its default (or an example if the default depends on your environment).
+Pass it to ` + "`gt new -c <file>`" + ` to generate a project without any questions.
+
+` + "```yaml" + `
+{{ .Example }}` + "```" + `
+{{- define "options" }}
+| Name | Description | Type | Default | Allowed values | Shown if | Files |
+| :--- | :---------- | :--- | :------ | :------------- | :------- | :---- |
+{{- range $index, $option := .Options}}
+| {{ $option.Name | code }} | {{ template "description" $option }} | {{ $option.Type | code }} | {{ default $option }} | {{ allowed $option }} | {{ shownIf $option }} | {{ files (key $.Category $option.Name) }} |
 {{- end}}
+{{- end }}
+{{- define "description" }}
+{{- .StaticDescription | replace "\n" "<br>" }}
+{{- with .StaticHelp }}<br>{{ . | replace "\n" "<br>" }}{{ end }}
+{{- end }}
 `
+	// none is shown in empty cells.
+	none = "-"
 )
 
+// markdownEscaper escapes text that should not be interpreted as markdown or HTML, e.g. patterns.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "|", `\|`, "<", `\<`) //nolint:gochecknoglobals,lll // constant replacer
+
 var (
 	errRequiredParameter = errors.New("`o` is a required parameter")
-	//nolint:gochecknoglobals // go template
-	funcMap = template.FuncMap{
+	errStale             = errors.New("is out of date, run \"make generate\"")
+)
+
+type docs struct {
+	*gotemplate.Options
+	// Files are the template's include rules by the options deciding about them.
+	Files map[string][]gotemplate.FileRule
+	// Example is an example values file.
+	Example string
+}
+
+type categoryOptions struct {
+	Category string
+	Options  []gotemplate.Option
+}
+
+func newTemplate(files map[string][]gotemplate.FileRule) *template.Template {
+	return template.Must(template.New("").Funcs(template.FuncMap{
 		"replace": func(old, new, src string) string {
 			return strings.ReplaceAll(src, old, new)
 		},
-		"code": func(s string) string {
-			return fmt.Sprintf("`%s`", s)
+		"code": code,
+		"options": func(category string, options []gotemplate.Option) categoryOptions {
+			return categoryOptions{Category: category, Options: options}
 		},
+		"key": func(category, name string) string {
+			if category == "" {
+				return name
+			}
+
+			return category + "." + name
+		},
+		"default": defaultValue,
+		"allowed": allowedValues,
+		"shownIf": func(option gotemplate.Option) string {
+			return joinOrNone(option.ShownIf(), code)
+		},
+		"files": func(key string) string {
+			var rules []string
+			for _, rule := range files[key] {
+				rules = append(rules, fmt.Sprintf("%s if %s", code(rule.Glob), code(rule.Condition)))
+			}
+
+			return joinOrNone(rules, func(s string) string { return s })
+		},
+	}).Parse(tmplString))
+}
+
+func code(s string) string {
+	return fmt.Sprintf("`%s`", s)
+}
+
+func joinOrNone(values []string, format func(string) string) string {
+	if len(values) == 0 {
+		return none
 	}
-	tmpl = template.Must(template.New("").Funcs(funcMap).Parse(tmplString)) //nolint:gochecknoglobals // go template
-)
+
+	formatted := make([]string, 0, len(values))
+	for _, value := range values {
+		formatted = append(formatted, format(value))
+	}
+
+	return strings.Join(formatted, "<br>")
+}
+
+// defaultValue describes the static default of option or how its dynamic default is computed.
+func defaultValue(option gotemplate.Option) string {
+	value, ok := option.StaticDefault()
+	if !ok {
+		return "Dynamic: " + markdownEscaper.Replace(option.DefaultDescription())
+	}
+
+	for _, choice := range option.Choices() {
+		if choice.Value == value {
+			return fmt.Sprintf("%s (%s)", code(fmt.Sprint(value)), choice.Description)
+		}
+	}
+
+	return code(fmt.Sprint(value))
+}
+
+// allowedValues lists the choices of option or describes the values its validator accepts.
+func allowedValues(option gotemplate.Option) string {
+	if choices := option.Choices(); len(choices) > 0 {
+		descriptions := make([]string, 0, len(choices))
+		for _, choice := range choices {
+			descriptions = append(descriptions, fmt.Sprintf("%s: %s", code(fmt.Sprint(choice.Value)), choice.Description))
+		}
+
+		return strings.Join(descriptions, "<br>")
+	}
+
+	if allowed := option.AllowedValues(); allowed != "" {
+		return markdownEscaper.Replace(allowed)
+	}
+
+	return "any"
+}
 
 // options2md tranlates all options available in go/template to a markdown file
 // defined by the -o flag. This can be used for documentation.
+// With -check it fails if the file is not up to date instead of writing it.
 func main() {
 	if err := run(os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "an error occurred: %s\n", err)
@@ -67,8 +174,13 @@ func main() {
 }
 
 func run(args []string) error {
-	var outputFile string
+	var (
+		outputFile string
+		check      bool
+	)
+
 	flag.StringVar(&outputFile, "o", "./options.md", "The file to write")
+	flag.BoolVar(&check, "check", false, "Fail if the file is not up to date instead of writing it")
 	err := flag.CommandLine.Parse(args)
 	if err != nil {
 		return err
@@ -78,10 +190,44 @@ func run(args []string) error {
 		return errRequiredParameter
 	}
 
-	file, err := os.Create(outputFile)
+	markdown, err := render(&gotemplate.GT{Options: gotemplate.NewOptions(nil)})
 	if err != nil {
 		return err
 	}
 
-	return tmpl.Execute(file, gotemplate.NewOptions(nil))
+	if check {
+		current, err := os.ReadFile(outputFile)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(current, markdown) {
+			return fmt.Errorf("%s %w", outputFile, errStale)
+		}
+
+		return nil
+	}
+
+	return os.WriteFile(outputFile, markdown, 0o644) //nolint:gomnd // rw-r--r--
+}
+
+func render(gt *gotemplate.GT) ([]byte, error) {
+	files, err := gt.FileRules()
+	if err != nil {
+		return nil, err
+	}
+
+	example := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(example)
+	encoder.SetIndent(2) //nolint:gomnd // same indentation as the docs' other yaml
+	if err := encoder.Encode(gt.Options.ExampleValues()); err != nil {
+		return nil, err
+	}
+
+	markdown := &bytes.Buffer{}
+	if err := newTemplate(files).Execute(markdown, docs{Options: gt.Options, Files: files, Example: example.String()}); err != nil {
+		return nil, err
+	}
+
+	return markdown.Bytes(), nil
 }
@@ -1,23 +1,44 @@
 package exec
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
+var (
+	ErrDependencyFailed  = errors.New("dependency failed")
+	ErrUnknownDependency = errors.New("unknown dependency")
+	ErrDependencyCycle   = errors.New("dependency cycle")
+	ErrDuplicateGroup    = errors.New("duplicate group name")
+)
+
 // CommandGroup contains commands that are run one after another.
 // As soon as one command fails the whole CommandGroup will be stopped and all other
 // not yet executed commands are skipped.
 type CommandGroup struct {
+	// Name is used to reference the group in DependsOn of other groups and in error messages.
+	Name string
+	// DependsOn contains the names of groups that need to succeed before this group is run by RunGroups.
+	DependsOn []string
 	// func run before any of the commands is executed
 	PreRun func() error
 	// Commands to run
 	Commands []*exec.Cmd
 	// TargetDir to run in
 	TargetDir string
+	// Timeout limits the time all commands of the group may take together.
+	// No limit is applied if it is zero.
+	Timeout time.Duration
+	// CommandTimeout limits the time a single command of the group may take.
+	// No limit is applied if it is zero.
+	CommandTimeout time.Duration
 }
 
 func (cg *CommandGroup) Run() error {
@@ -25,6 +46,13 @@ func (cg *CommandGroup) Run() error {
 }
 
 func (cg *CommandGroup) RunWith(runner CmdRunner) error {
+	return cg.RunWithContext(context.Background(), runner)
+}
+
+// RunWithContext runs all commands of the group with runner.
+// Once ctx is done or one of the timeouts is exceeded the running command is killed
+// (if runner implements ContextCmdRunner) and all remaining commands are skipped.
+func (cg *CommandGroup) RunWithContext(ctx context.Context, runner CmdRunner) error {
 	if len(cg.Commands) == 0 {
 		return nil
 	}
@@ -40,15 +68,152 @@ func (cg *CommandGroup) RunWith(runner CmdRunner) error {
 		}
 	}
 
+	if cg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cg.Timeout)
+		defer cancel()
+	}
+
+	ctx = contextWithGroup(ctx, cg.Name)
 	for _, cmd := range cg.Commands {
 		if cg.TargetDir != "" {
 			cmd.Dir = cg.TargetDir
 		}
 
-		if _, err := runner.Run(cmd); err != nil {
+		if err := cg.runCommand(ctx, runner, cmd); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+func (cg *CommandGroup) runCommand(ctx context.Context, runner CmdRunner, cmd *exec.Cmd) error {
+	if cg.CommandTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cg.CommandTimeout)
+		defer cancel()
+	}
+
+	_, err := runContext(ctx, runner, cmd)
+
+	return err
+}
+
+// RunGroups runs all groups concurrently with runner.
+// A group is only started after all groups listed in its DependsOn succeeded,
+// if any of them fails the group is skipped with ErrDependencyFailed.
+// The errors of all failed groups are joined and returned.
+func RunGroups(ctx context.Context, runner CmdRunner, groups ...*CommandGroup) error {
+	indexByName, err := indexGroups(groups)
+	if err != nil {
+		return err
+	}
+
+	done := make([]chan struct{}, len(groups))
+	for i := range groups {
+		done[i] = make(chan struct{})
+	}
+
+	errs := make([]error, len(groups))
+
+	var wg sync.WaitGroup
+	for i, cg := range groups {
+		wg.Add(1)
+		go func(i int, cg *CommandGroup) {
+			defer wg.Done()
+			defer close(done[i])
+
+			for _, dependency := range cg.DependsOn {
+				depIndex := indexByName[dependency]
+				<-done[depIndex]
+
+				if errs[depIndex] != nil {
+					errs[i] = errors.Wrapf(ErrDependencyFailed, "skipping %s because of %s", groupName(cg, i), dependency)
+					return
+				}
+			}
+
+			if err := cg.RunWithContext(ctx, runner); err != nil {
+				errs[i] = errors.Wrap(err, groupName(cg, i))
+			}
+		}(i, cg)
+	}
+
+	wg.Wait()
+
+	return stderrors.Join(errs...)
+}
+
+// indexGroups maps the names of the groups to their index.
+// It makes sure that names are unique, all dependencies reference existing groups and that
+// there are no cycles, which would otherwise block RunGroups forever.
+func indexGroups(groups []*CommandGroup) (map[string]int, error) {
+	indexByName := make(map[string]int, len(groups))
+	for i, cg := range groups {
+		if cg.Name == "" {
+			continue
+		}
+
+		if _, ok := indexByName[cg.Name]; ok {
+			return nil, errors.Wrap(ErrDuplicateGroup, cg.Name)
+		}
+
+		indexByName[cg.Name] = i
+	}
+
+	for i, cg := range groups {
+		for _, dependency := range cg.DependsOn {
+			if _, ok := indexByName[dependency]; !ok {
+				return nil, errors.Wrapf(ErrUnknownDependency, "%s depends on %q", groupName(cg, i), dependency)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(groups))
+
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		path = append(path, groupName(groups[i], i))
+
+		switch state[i] {
+		case visiting:
+			return errors.Wrap(ErrDependencyCycle, strings.Join(path, " -> "))
+		case visited:
+			return nil
+		case unvisited:
+		}
+
+		state[i] = visiting
+		for _, dependency := range groups[i].DependsOn {
+			if err := visit(indexByName[dependency], path); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+
+		return nil
+	}
+
+	for i := range groups {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return indexByName, nil
+}
+
+func groupName(cg *CommandGroup, index int) string {
+	if cg.Name != "" {
+		return cg.Name
+	}
+
+	return fmt.Sprintf("group %d", index)
+}
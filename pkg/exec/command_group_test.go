@@ -1,9 +1,12 @@
 package exec_test
 
 import (
+	"context"
 	"errors"
 	"os/exec"
+	"sync"
 	"testing"
+	"time"
 
 	ownexec "github.com/schwarzit/go-template/pkg/exec"
 	"github.com/stretchr/testify/require"
@@ -79,3 +82,150 @@ func Test_CommandGroup_RunWith(t *testing.T) {
 		require.False(t, sthelseExecuted)
 	})
 }
+
+func Test_CommandGroup_RunWithContext(t *testing.T) {
+	t.Run("no command is executed if context is already done", func(t *testing.T) {
+		cg := ownexec.CommandGroup{
+			Commands: []*exec.Cmd{
+				exec.Command("anything"),
+			},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		anyCommandExecuted := false
+		err := cg.RunWithContext(ctx, ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+			anyCommandExecuted = true
+			return "", nil
+		}))
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.False(t, anyCommandExecuted)
+	})
+	t.Run("command is killed after command timeout", func(t *testing.T) {
+		cg := ownexec.CommandGroup{
+			Commands: []*exec.Cmd{
+				exec.Command("sleep", "10"),
+			},
+			CommandTimeout: 100 * time.Millisecond,
+		}
+
+		start := time.Now()
+		err := cg.RunWithContext(context.Background(), ownexec.NewExecCmdRunner())
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+	t.Run("remaining commands are skipped after group timeout", func(t *testing.T) {
+		cg := ownexec.CommandGroup{
+			Commands: []*exec.Cmd{
+				exec.Command("sleep", "10"),
+				exec.Command("anything"),
+			},
+			Timeout: 100 * time.Millisecond,
+		}
+
+		err := cg.RunWithContext(context.Background(), ownexec.NewExecCmdRunner())
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.NotErrorIs(t, err, exec.ErrNotFound)
+	})
+}
+
+func Test_RunGroups(t *testing.T) {
+	t.Run("groups run after their dependencies", func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			executed []string
+		)
+		runner := ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			executed = append(executed, cmd.Path)
+			return "", nil
+		})
+
+		err := ownexec.RunGroups(
+			context.Background(),
+			runner,
+			&ownexec.CommandGroup{Name: "second", DependsOn: []string{"first"}, Commands: []*exec.Cmd{exec.Command("second")}},
+			&ownexec.CommandGroup{Name: "first", Commands: []*exec.Cmd{exec.Command("first")}},
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"first", "second"}, executed)
+	})
+	t.Run("independent groups run concurrently", func(t *testing.T) {
+		started := make(chan struct{})
+		runner := ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+			switch cmd.Path {
+			case "waiting":
+				select {
+				case <-started:
+					return "", nil
+				case <-time.After(5 * time.Second):
+					return "", errDummy
+				}
+			case "starting":
+				close(started)
+			}
+			return "", nil
+		})
+
+		err := ownexec.RunGroups(
+			context.Background(),
+			runner,
+			&ownexec.CommandGroup{Name: "waiting", Commands: []*exec.Cmd{exec.Command("waiting")}},
+			&ownexec.CommandGroup{Name: "starting", Commands: []*exec.Cmd{exec.Command("starting")}},
+		)
+
+		require.NoError(t, err)
+	})
+	t.Run("dependent groups are skipped if a dependency fails", func(t *testing.T) {
+		dependentExecuted, independentExecuted := false, false
+		runner := ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+			switch cmd.Path {
+			case "failing":
+				return "", errDummy
+			case "dependent":
+				dependentExecuted = true
+			case "independent":
+				independentExecuted = true
+			}
+			return "", nil
+		})
+
+		err := ownexec.RunGroups(
+			context.Background(),
+			runner,
+			&ownexec.CommandGroup{Name: "failing", Commands: []*exec.Cmd{exec.Command("failing")}},
+			&ownexec.CommandGroup{Name: "dependent", DependsOn: []string{"failing"}, Commands: []*exec.Cmd{exec.Command("dependent")}},
+			&ownexec.CommandGroup{Name: "independent", Commands: []*exec.Cmd{exec.Command("independent")}},
+		)
+
+		require.ErrorIs(t, err, errDummy)
+		require.ErrorIs(t, err, ownexec.ErrDependencyFailed)
+		require.False(t, dependentExecuted)
+		require.True(t, independentExecuted)
+	})
+	t.Run("error on unknown dependency", func(t *testing.T) {
+		err := ownexec.RunGroups(
+			context.Background(),
+			ownexec.NewExecCmdRunner(),
+			&ownexec.CommandGroup{Name: "group", DependsOn: []string{"unknown"}},
+		)
+
+		require.ErrorIs(t, err, ownexec.ErrUnknownDependency)
+	})
+	t.Run("error on dependency cycle", func(t *testing.T) {
+		err := ownexec.RunGroups(
+			context.Background(),
+			ownexec.NewExecCmdRunner(),
+			&ownexec.CommandGroup{Name: "a", DependsOn: []string{"b"}},
+			&ownexec.CommandGroup{Name: "b", DependsOn: []string{"a"}},
+		)
+
+		require.ErrorIs(t, err, ownexec.ErrDependencyCycle)
+	})
+}
@@ -0,0 +1,20 @@
+//go:build !unix
+
+package exec
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on platforms without process groups.
+func setProcessGroup(_ *exec.Cmd) {}
+
+// killProcessGroup kills the process of an already started command.
+// Child processes are not killed on platforms without process groups.
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,43 @@
+//go:build unix
+
+package exec
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/mattn/go-isatty"
+)
+
+// setProcessGroup starts the command in its own process group
+// so that it can be killed together with all of its children.
+// This is skipped if gt runs in a terminal since only the foreground process group receives ^C there
+// and commands in other groups are stopped (SIGTTIN) as soon as they prompt, e.g. for credentials.
+func setProcessGroup(cmd *exec.Cmd) {
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		return
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// killProcessGroup kills the whole process group of an already started command.
+// Commands that weren't started in their own process group (see setProcessGroup) are killed on their own,
+// their children get ^C from the terminal and exec.Cmd.WaitDelay makes sure they can't block.
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+
+	if cmd.SysProcAttr == nil || !cmd.SysProcAttr.Setpgid {
+		return cmd.Process.Kill()
+	}
+
+	// a negative pid addresses the whole process group
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -2,14 +2,26 @@ package exec
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
+// waitDelay is the time to wait for the output pipes of a command to be closed
+// after the process exited or has been killed.
+const waitDelay = 5 * time.Second
+
 var (
-	_ CmdRunner = (*execCmdRunner)(nil)
-	_ CmdRunner = CmdRunnerFunc(nil)
+	_ CmdRunner        = (*execCmdRunner)(nil)
+	_ ContextCmdRunner = (*execCmdRunner)(nil)
+	_ CmdRunner        = CmdRunnerFunc(nil)
 )
 
 // CmdRunner is an interface to safely abstract exec.Cmd calls.
@@ -17,19 +29,67 @@ type CmdRunner interface {
 	Run(cmd *exec.Cmd) (string, error)
 }
 
+// ContextCmdRunner is a CmdRunner that is able to abort a running command
+// as soon as the passed context is done.
+// Commands of the runner returned by NewExecCmdRunner are only started in their own process group,
+// which allows killing their children as well, if stdin isn't a terminal.
+// In a terminal they stay in the foreground group to receive ^C and to be able to prompt for input.
+type ContextCmdRunner interface {
+	CmdRunner
+	RunContext(ctx context.Context, cmd *exec.Cmd) (string, error)
+}
+
 type CmdRunnerFunc func(cmd *exec.Cmd) (string, error)
 
 func (f CmdRunnerFunc) Run(cmd *exec.Cmd) (string, error) {
 	return f(cmd)
 }
 
-type execCmdRunner struct{}
+type execCmdRunner struct {
+	// stdout and stderr additionally receive the command's output while it is running if set.
+	stdout, stderr io.Writer
+	// mu synchronizes writes to stdout and stderr since commands might run concurrently.
+	mu sync.Mutex
+}
 
 func (r *execCmdRunner) Run(cmd *exec.Cmd) (string, error) {
+	return r.RunContext(context.Background(), cmd)
+}
+
+// RunContext runs the command and kills it as soon as ctx is done.
+// cmd.Stdout and cmd.Stderr additionally receive the output if they are set.
+// Its child processes are killed as well if it runs in its own process group, see setProcessGroup.
+func (r *execCmdRunner) RunContext(ctx context.Context, cmd *exec.Cmd) (string, error) {
 	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
-	cmd.Stdout, cmd.Stderr = stdout, stderr
+	// writers set by the caller additionally receive the output, e.g. to record it
+	outWriters, errWriters := []io.Writer{stdout}, []io.Writer{stderr}
+
+	if cmd.Stdout != nil {
+		outWriters = append(outWriters, cmd.Stdout)
+	}
+
+	if cmd.Stderr != nil {
+		errWriters = append(errWriters, cmd.Stderr)
+	}
+
+	if r.stdout != nil {
+		streamOut := r.newPrefixWriter(r.stdout, cmd)
+		defer streamOut.Flush()
+		outWriters = append(outWriters, streamOut)
+	}
+
+	if r.stderr != nil {
+		streamErr := r.newPrefixWriter(r.stderr, cmd)
+		defer streamErr.Flush()
+		errWriters = append(errWriters, streamErr)
+	}
 
-	if err := cmd.Run(); err != nil {
+	cmd.Stdout, cmd.Stderr = io.MultiWriter(outWriters...), io.MultiWriter(errWriters...)
+
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
 		return "", &ErrWithStderr{
 			Wrapped: err,
 			Args:    cmd.Args,
@@ -37,6 +97,28 @@ func (r *execCmdRunner) Run(cmd *exec.Cmd) (string, error) {
 		}
 	}
 
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-waitErr:
+		if err != nil {
+			return "", &ErrWithStderr{
+				Wrapped: err,
+				Args:    cmd.Args,
+				StdErr:  stderr.Bytes(),
+			}
+		}
+	case <-ctx.Done():
+		// ignore error since the process might have exited in the meantime
+		_ = killProcessGroup(cmd)
+		<-waitErr
+
+		return "", errors.Wrapf(ctx.Err(), "aborted `%s`", strings.Join(cmd.Args, " "))
+	}
+
 	return stdout.String(), nil
 }
 
@@ -44,6 +126,77 @@ func NewExecCmdRunner() CmdRunner {
 	return &execCmdRunner{}
 }
 
+// NewStreamingCmdRunner returns a CmdRunner that streams the output of the commands to stdout and stderr
+// while they are running. Every line is prefixed with the name of the command that wrote it.
+// The output is captured nonetheless, so the returned stdout and errors are the same as with NewExecCmdRunner.
+func NewStreamingCmdRunner(stdout, stderr io.Writer) CmdRunner {
+	return &execCmdRunner{stdout: stdout, stderr: stderr}
+}
+
+func (r *execCmdRunner) newPrefixWriter(w io.Writer, cmd *exec.Cmd) *prefixWriter {
+	return &prefixWriter{
+		w:      w,
+		mu:     &r.mu,
+		prefix: []byte(fmt.Sprintf("[%s] ", filepath.Base(cmd.Args[0]))),
+	}
+}
+
+// prefixWriter writes every line prefixed to w.
+// Lines are only written once they are complete to not mix up output of concurrent commands.
+// Errors of w are ignored since streaming is best effort and must not abort the command.
+type prefixWriter struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	prefix []byte
+	buffer []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buffer = append(p.buffer, b...)
+
+	for {
+		i := bytes.IndexByte(p.buffer, '\n')
+		if i < 0 {
+			return len(b), nil
+		}
+
+		p.writeLine(p.buffer[:i+1])
+		p.buffer = p.buffer[i+1:]
+	}
+}
+
+// Flush writes an incomplete last line if there is any.
+func (p *prefixWriter) Flush() {
+	if len(p.buffer) == 0 {
+		return
+	}
+
+	p.writeLine(append(p.buffer, '\n'))
+	p.buffer = nil
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, _ = p.w.Write(append(append([]byte{}, p.prefix...), line...))
+}
+
+// runContext runs cmd with runner and passes ctx along if the runner supports it.
+// Runners that don't implement ContextCmdRunner can't be interrupted, but no further
+// command is started once ctx is done.
+func runContext(ctx context.Context, runner CmdRunner, cmd *exec.Cmd) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", errors.Wrapf(err, "skipping `%s`", strings.Join(cmd.Args, " "))
+	}
+
+	if ctxRunner, ok := runner.(ContextCmdRunner); ok {
+		return ctxRunner.RunContext(ctx, cmd)
+	}
+
+	return runner.Run(cmd)
+}
+
 type ErrWithStderr struct {
 	Wrapped error
 	StdErr  []byte
@@ -1,10 +1,15 @@
 package exec_test
 
 import (
+	"bytes"
+	"context"
+	"os"
 	"os/exec"
 	"runtime"
 	"testing"
+	"time"
 
+	"github.com/mattn/go-isatty"
 	ownexec "github.com/schwarzit/go-template/pkg/exec"
 	"github.com/stretchr/testify/require"
 )
@@ -21,4 +26,76 @@ func Test_execCmdRunner_Run(t *testing.T) {
 		require.NoError(t, err)
 		require.Contains(t, output, runtime.Version())
 	})
+	t.Run("additionally writes output to the command's writers", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		cmd := exec.Command("go", "version")
+		cmd.Stdout = stdout
+
+		output, err := ownexec.NewExecCmdRunner().Run(cmd)
+		require.NoError(t, err)
+		require.Equal(t, output, stdout.String())
+	})
+}
+
+func Test_execCmdRunner_RunContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("relies on sh")
+	}
+
+	t.Run("kills command when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		runner := ownexec.NewExecCmdRunner().(ownexec.ContextCmdRunner)
+
+		start := time.Now()
+		_, err := runner.RunContext(ctx, exec.Command("sleep", "10"))
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+	t.Run("kills command and its children when context is done", func(t *testing.T) {
+		if isatty.IsTerminal(os.Stdin.Fd()) {
+			t.Skip("commands stay in the foreground process group in a terminal")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		runner := ownexec.NewExecCmdRunner().(ownexec.ContextCmdRunner)
+
+		start := time.Now()
+		// the child keeps the output pipe open, so this would block if only sh got killed
+		_, err := runner.RunContext(ctx, exec.Command("sh", "-c", "sleep 10 & wait"))
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+}
+
+func Test_streamingCmdRunner_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("relies on sh")
+	}
+
+	t.Run("streams prefixed output and still captures it", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+		output, err := ownexec.NewStreamingCmdRunner(stdout, stderr).Run(exec.Command("sh", "-c", "echo out; echo err >&2; printf partial"))
+
+		require.NoError(t, err)
+		require.Equal(t, "out\npartial", output)
+		require.Equal(t, "[sh] out\n[sh] partial\n", stdout.String())
+		require.Equal(t, "[sh] err\n", stderr.String())
+	})
+	t.Run("captures stderr in error", func(t *testing.T) {
+		stderr := &bytes.Buffer{}
+
+		_, err := ownexec.NewStreamingCmdRunner(&bytes.Buffer{}, stderr).Run(exec.Command("sh", "-c", "echo failure >&2; exit 1"))
+
+		var errWithStderr *ownexec.ErrWithStderr
+		require.ErrorAs(t, err, &errWithStderr)
+		require.Equal(t, "failure\n", string(errWithStderr.StdErr))
+		require.Equal(t, "[sh] failure\n", stderr.String())
+	})
 }
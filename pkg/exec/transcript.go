@@ -0,0 +1,328 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	permissionRW = 0644
+	// baseDirPlaceholder replaces the base dir in the recorded output, see RecordedCommand.
+	baseDirPlaceholder = "$BASE_DIR"
+)
+
+var (
+	_ ContextCmdRunner = (*RecordingCmdRunner)(nil)
+	_ ContextCmdRunner = (*ReplayingCmdRunner)(nil)
+
+	ErrUnexpectedCommand = errors.New("unexpected command")
+	ErrCommandOutOfOrder = errors.New("command out of order")
+	ErrCommandsNotRun    = errors.New("recorded commands not run")
+)
+
+// Transcript contains recorded command executions in the order they were run.
+type Transcript struct {
+	Commands []RecordedCommand `yaml:"commands"`
+}
+
+// RecordedCommand is a single command execution of a Transcript.
+// Occurrences of the base dir of the recording in the output and the error are replaced by $BASE_DIR.
+type RecordedCommand struct {
+	// Group is the name of the CommandGroup the command was run in.
+	// Commands are only ordered within their group since groups might run concurrently.
+	Group string   `yaml:"group,omitempty"`
+	Args  []string `yaml:"args"`
+	// Dir is relative to the base dir of the recording.
+	Dir string   `yaml:"dir,omitempty"`
+	Env []string `yaml:"env,omitempty"`
+	// Stdout is the output returned by the runner.
+	Stdout string `yaml:"stdout,omitempty"`
+	// Stderr is recorded for all commands if the runner writes it to exec.Cmd.Stderr like NewExecCmdRunner does,
+	// otherwise it is only known for failed commands from their ErrWithStderr.
+	Stderr   string `yaml:"stderr,omitempty"`
+	ExitCode int    `yaml:"exitCode,omitempty"`
+	// Error is the message of the error returned by the runner, if any.
+	Error string `yaml:"error,omitempty"`
+}
+
+// LoadTranscript reads a Transcript from a YAML file.
+func LoadTranscript(file string) (*Transcript, error) {
+	fileBytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var transcript Transcript
+	if err := yaml.Unmarshal(fileBytes, &transcript); err != nil {
+		return nil, errors.Wrap(err, file)
+	}
+
+	return &transcript, nil
+}
+
+// WriteFile writes the Transcript as YAML to file.
+func (t *Transcript) WriteFile(file string) error {
+	fileBytes, err := yaml.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, fileBytes, permissionRW)
+}
+
+// RecordingCmdRunner runs commands with another CmdRunner and records them into a Transcript.
+type RecordingCmdRunner struct {
+	runner  CmdRunner
+	baseDir string
+
+	mu         sync.Mutex
+	transcript Transcript
+}
+
+// NewRecordingCmdRunner returns a RecordingCmdRunner running commands with runner.
+// Working directories are recorded relative to baseDir to make transcripts independent of the machine.
+func NewRecordingCmdRunner(runner CmdRunner, baseDir string) *RecordingCmdRunner {
+	return &RecordingCmdRunner{runner: runner, baseDir: baseDir}
+}
+
+func (r *RecordingCmdRunner) Run(cmd *exec.Cmd) (string, error) {
+	return r.RunContext(context.Background(), cmd)
+}
+
+func (r *RecordingCmdRunner) RunContext(ctx context.Context, cmd *exec.Cmd) (string, error) {
+	stderr := &bytes.Buffer{}
+	if cmd.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(stderr, cmd.Stderr)
+	} else {
+		cmd.Stderr = stderr
+	}
+
+	stdout, err := runContext(ctx, r.runner, cmd)
+
+	recorded := RecordedCommand{
+		Group:  groupFromContext(ctx),
+		Args:   cmd.Args,
+		Dir:    relativeDir(r.baseDir, cmd.Dir),
+		Env:    cmd.Env,
+		Stdout: stdout,
+		Stderr: stderr.String(),
+	}
+
+	if err != nil {
+		recorded.Error = err.Error()
+
+		var errWithStderr *ErrWithStderr
+		if errors.As(err, &errWithStderr) {
+			recorded.Error = errWithStderr.Wrapped.Error()
+			if recorded.Stderr == "" {
+				recorded.Stderr = string(errWithStderr.StdErr)
+			}
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			recorded.ExitCode = exitErr.ExitCode()
+		}
+	}
+
+	r.mu.Lock()
+	r.transcript.Commands = append(r.transcript.Commands, recorded.replaceInOutput(r.baseDir, baseDirPlaceholder))
+	r.mu.Unlock()
+
+	return stdout, err
+}
+
+// Transcript returns all commands recorded so far.
+func (r *RecordingCmdRunner) Transcript() *Transcript {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &Transcript{Commands: append([]RecordedCommand{}, r.transcript.Commands...)}
+}
+
+// ReplayingCmdRunner serves the results of a Transcript instead of running commands.
+// It fails on commands that are not part of the transcript or are run out of order.
+type ReplayingCmdRunner struct {
+	transcript *Transcript
+	baseDir    string
+
+	mu       sync.Mutex
+	replayed []bool
+}
+
+// NewReplayingCmdRunner returns a ReplayingCmdRunner for transcript.
+// Working directories of commands are compared relative to baseDir.
+func NewReplayingCmdRunner(transcript *Transcript, baseDir string) *ReplayingCmdRunner {
+	return &ReplayingCmdRunner{
+		transcript: transcript,
+		baseDir:    baseDir,
+		replayed:   make([]bool, len(transcript.Commands)),
+	}
+}
+
+func (r *ReplayingCmdRunner) Run(cmd *exec.Cmd) (string, error) {
+	return r.RunContext(context.Background(), cmd)
+}
+
+func (r *ReplayingCmdRunner) RunContext(ctx context.Context, cmd *exec.Cmd) (string, error) {
+	actual := RecordedCommand{
+		Group: groupFromContext(ctx),
+		Args:  cmd.Args,
+		Dir:   relativeDir(r.baseDir, cmd.Dir),
+		Env:   cmd.Env,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the next expected command is the first one of the same group that has not been replayed yet
+	next := -1
+	for i, recorded := range r.transcript.Commands {
+		if r.replayed[i] || recorded.Group != actual.Group {
+			continue
+		}
+
+		if next < 0 {
+			next = i
+		}
+
+		if !recorded.matches(actual) {
+			continue
+		}
+
+		if i != next {
+			return "", errors.Wrapf(ErrCommandOutOfOrder, "got %s, expected %s", actual, r.transcript.Commands[next])
+		}
+
+		r.replayed[i] = true
+
+		return recorded.replaceInOutput(baseDirPlaceholder, r.baseDir).replay(cmd)
+	}
+
+	if next < 0 {
+		return "", errors.Wrapf(ErrUnexpectedCommand, "got %s, no further commands recorded", actual)
+	}
+
+	return "", errors.Wrapf(ErrUnexpectedCommand, "got %s, expected %s", actual, r.transcript.Commands[next])
+}
+
+// Done returns an error if any of the recorded commands has not been replayed.
+func (r *ReplayingCmdRunner) Done() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var missing []string
+	for i, recorded := range r.transcript.Commands {
+		if !r.replayed[i] {
+			missing = append(missing, recorded.String())
+		}
+	}
+
+	if len(missing) > 0 {
+		return errors.Wrap(ErrCommandsNotRun, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func (c RecordedCommand) matches(other RecordedCommand) bool {
+	return reflect.DeepEqual(c.Args, other.Args) &&
+		c.Dir == other.Dir &&
+		reflect.DeepEqual(c.Env, other.Env)
+}
+
+// replay writes the recorded output to cmd.Stdout and cmd.Stderr (if set) like a runner would
+// and returns the recorded result.
+func (c RecordedCommand) replay(cmd *exec.Cmd) (string, error) {
+	// errors are ignored like for streamed output since the recorded result is returned regardless
+	if cmd.Stdout != nil {
+		_, _ = io.WriteString(cmd.Stdout, c.Stdout)
+	}
+
+	if cmd.Stderr != nil {
+		_, _ = io.WriteString(cmd.Stderr, c.Stderr)
+	}
+
+	return c.result()
+}
+
+func (c RecordedCommand) result() (string, error) {
+	if c.Error == "" && c.ExitCode == 0 {
+		return c.Stdout, nil
+	}
+
+	message := c.Error
+	if message == "" {
+		message = fmt.Sprintf("exit status %d", c.ExitCode)
+	}
+
+	return c.Stdout, &ErrWithStderr{
+		Wrapped: errors.New(message),
+		Args:    c.Args,
+		StdErr:  []byte(c.Stderr),
+	}
+}
+
+// replaceInOutput returns a copy of c with from replaced by to in its output and error.
+func (c RecordedCommand) replaceInOutput(from, to string) RecordedCommand {
+	if from == "" || to == "" {
+		return c
+	}
+
+	c.Stdout = strings.ReplaceAll(c.Stdout, from, to)
+	c.Stderr = strings.ReplaceAll(c.Stderr, from, to)
+	c.Error = strings.ReplaceAll(c.Error, from, to)
+
+	return c
+}
+
+func (c RecordedCommand) String() string {
+	s := "`" + strings.Join(c.Args, " ") + "`"
+	if c.Dir != "" {
+		s += " in " + c.Dir
+	}
+
+	if c.Group != "" {
+		s += " (group " + c.Group + ")"
+	}
+
+	return s
+}
+
+// relativeDir returns dir relative to baseDir with forward slashes.
+// If that is not possible dir is returned as is.
+func relativeDir(baseDir, dir string) string {
+	if baseDir == "" || dir == "" {
+		return dir
+	}
+
+	rel, err := filepath.Rel(baseDir, dir)
+	if err != nil {
+		return dir
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+type groupContextKey struct{}
+
+// contextWithGroup stores the name of the CommandGroup the commands are run in.
+func contextWithGroup(ctx context.Context, group string) context.Context {
+	return context.WithValue(ctx, groupContextKey{}, group)
+}
+
+func groupFromContext(ctx context.Context) string {
+	group, _ := ctx.Value(groupContextKey{}).(string)
+	return group
+}
@@ -0,0 +1,146 @@
+package exec_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	ownexec "github.com/schwarzit/go-template/pkg/exec"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RecordingCmdRunner(t *testing.T) {
+	baseDir := t.TempDir()
+
+	recorder := ownexec.NewRecordingCmdRunner(ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+		switch cmd.Path {
+		case "failing":
+			return "", &ownexec.ErrWithStderr{Wrapped: errDummy, Args: cmd.Args, StdErr: []byte("some stderr")}
+		case "warning":
+			_, _ = io.WriteString(cmd.Stderr, "some warning")
+			return "wrote " + cmd.Dir, nil
+		default:
+			return "some stdout", nil
+		}
+	}), baseDir)
+
+	cg := ownexec.CommandGroup{
+		Name: "group",
+		Commands: []*exec.Cmd{
+			exec.Command("succeeding", "arg"),
+			exec.Command("warning"),
+			exec.Command("failing"),
+		},
+		TargetDir: filepath.Join(baseDir, "project"),
+	}
+
+	err := cg.RunWith(recorder)
+	require.ErrorIs(t, err, errDummy)
+
+	transcriptFile := filepath.Join(t.TempDir(), "transcript.yml")
+	require.NoError(t, recorder.Transcript().WriteFile(transcriptFile))
+
+	transcript, err := ownexec.LoadTranscript(transcriptFile)
+	require.NoError(t, err)
+	require.Equal(t, &ownexec.Transcript{
+		Commands: []ownexec.RecordedCommand{
+			{Group: "group", Args: []string{"succeeding", "arg"}, Dir: "project", Stdout: "some stdout"},
+			{Group: "group", Args: []string{"warning"}, Dir: "project", Stdout: "wrote $BASE_DIR/project", Stderr: "some warning"},
+			{Group: "group", Args: []string{"failing"}, Dir: "project", Stderr: "some stderr", Error: errDummy.Error()},
+		},
+	}, transcript)
+}
+
+func Test_ReplayingCmdRunner(t *testing.T) {
+	baseDir := t.TempDir()
+	transcript := &ownexec.Transcript{
+		Commands: []ownexec.RecordedCommand{
+			{Group: "first", Args: []string{"one"}, Stdout: "output of one"},
+			{Group: "first", Args: []string{"two"}, Stderr: "failure", ExitCode: 1},
+			{Group: "second", Args: []string{"three"}, Dir: "project"},
+		},
+	}
+
+	t.Run("replays recorded results", func(t *testing.T) {
+		replayer := ownexec.NewReplayingCmdRunner(transcript, baseDir)
+
+		// groups are ordered independently of each other
+		err := (&ownexec.CommandGroup{
+			Name:      "second",
+			Commands:  []*exec.Cmd{exec.Command("three")},
+			TargetDir: filepath.Join(baseDir, "project"),
+		}).RunWith(replayer)
+		require.NoError(t, err)
+
+		err = (&ownexec.CommandGroup{
+			Name:     "first",
+			Commands: []*exec.Cmd{exec.Command("one"), exec.Command("two")},
+		}).RunWith(replayer)
+
+		var errWithStderr *ownexec.ErrWithStderr
+		require.ErrorAs(t, err, &errWithStderr)
+		require.Equal(t, "failure", string(errWithStderr.StdErr))
+		require.NoError(t, replayer.Done())
+	})
+	t.Run("returns recorded stdout", func(t *testing.T) {
+		replayer := ownexec.NewReplayingCmdRunner(&ownexec.Transcript{
+			Commands: []ownexec.RecordedCommand{
+				{Args: []string{"one"}, Stdout: "output of one in $BASE_DIR"},
+			},
+		}, baseDir)
+
+		output, err := replayer.Run(exec.Command("one"))
+		require.NoError(t, err)
+		require.Equal(t, "output of one in "+baseDir, output)
+	})
+	t.Run("writes recorded stderr of succeeding commands", func(t *testing.T) {
+		replayer := ownexec.NewReplayingCmdRunner(&ownexec.Transcript{
+			Commands: []ownexec.RecordedCommand{
+				{Args: []string{"one"}, Stderr: "warning of one"},
+			},
+		}, baseDir)
+
+		stderr := &bytes.Buffer{}
+		cmd := exec.Command("one")
+		cmd.Stderr = stderr
+
+		_, err := replayer.Run(cmd)
+		require.NoError(t, err)
+		require.Equal(t, "warning of one", stderr.String())
+	})
+	t.Run("commands outside of groups don't match grouped recordings", func(t *testing.T) {
+		replayer := ownexec.NewReplayingCmdRunner(transcript, baseDir)
+
+		_, err := replayer.RunContext(context.Background(), exec.Command("one"))
+		require.ErrorIs(t, err, ownexec.ErrUnexpectedCommand)
+	})
+	t.Run("error on out of order command", func(t *testing.T) {
+		replayer := ownexec.NewReplayingCmdRunner(transcript, baseDir)
+
+		err := (&ownexec.CommandGroup{
+			Name:     "first",
+			Commands: []*exec.Cmd{exec.Command("two")},
+		}).RunWith(replayer)
+
+		require.ErrorIs(t, err, ownexec.ErrCommandOutOfOrder)
+	})
+	t.Run("error on unexpected command", func(t *testing.T) {
+		replayer := ownexec.NewReplayingCmdRunner(transcript, baseDir)
+
+		err := (&ownexec.CommandGroup{
+			Name:      "second",
+			Commands:  []*exec.Cmd{exec.Command("three")},
+			TargetDir: filepath.Join(baseDir, "other"),
+		}).RunWith(replayer)
+
+		require.ErrorIs(t, err, ownexec.ErrUnexpectedCommand)
+	})
+	t.Run("error if not all commands were run", func(t *testing.T) {
+		replayer := ownexec.NewReplayingCmdRunner(transcript, baseDir)
+
+		require.ErrorIs(t, replayer.Done(), ownexec.ErrCommandsNotRun)
+	})
+}
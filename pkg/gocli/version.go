@@ -1,36 +1,37 @@
 package gocli
 
 import (
-	"bytes"
 	"os/exec"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/pkg/errors"
+
+	ownexec "github.com/schwarzit/go-template/pkg/exec"
 )
 
 var ErrMalformedGoVersionOutput = errors.New("malformed go version output")
 
 func Semver() (*semver.Version, error) {
-	stdout := &bytes.Buffer{}
-
-	goVersion := exec.Command("go", "version")
-	goVersion.Stdout = stdout
+	return SemverWith(ownexec.NewExecCmdRunner())
+}
 
-	err := goVersion.Run()
+// SemverWith returns the version of the go cli by running `go version` with runner.
+func SemverWith(runner ownexec.CmdRunner) (*semver.Version, error) {
+	stdout, err := runner.Run(exec.Command("go", "version"))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed checking go version")
 	}
 
-	versionParts := strings.Split(stdout.String(), " ")
+	versionParts := strings.Split(stdout, " ")
 	if len(versionParts) != 4 { //nolint:gomnd // go version output has exactly 4 parts (e.g. "go version go1.17.2 darwin/amd64")
-		return nil, errors.Wrap(ErrMalformedGoVersionOutput, stdout.String())
+		return nil, errors.Wrap(ErrMalformedGoVersionOutput, stdout)
 	}
 
 	goSemverString := strings.TrimPrefix(versionParts[2], "go")
 	goSemver, err := semver.NewVersion(goSemverString)
 	if err != nil {
-		return nil, errors.Wrap(ErrMalformedGoVersionOutput, stdout.String())
+		return nil, errors.Wrap(ErrMalformedGoVersionOutput, stdout)
 	}
 
 	return goSemver, nil
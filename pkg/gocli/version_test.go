@@ -1,11 +1,13 @@
 package gocli_test
 
 import (
+	"os/exec"
 	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/Masterminds/semver/v3"
+	ownexec "github.com/schwarzit/go-template/pkg/exec"
 	"github.com/schwarzit/go-template/pkg/gocli"
 	"github.com/stretchr/testify/require"
 )
@@ -17,3 +19,19 @@ func Test_Semver(t *testing.T) {
 	runtimeVersion := semver.MustParse(strings.TrimPrefix(runtime.Version(), "go"))
 	require.True(t, version.Equal(runtimeVersion))
 }
+
+func Test_SemverWith(t *testing.T) {
+	t.Run("parses output of runner", func(t *testing.T) {
+		version, err := gocli.SemverWith(ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+			return "go version go1.21.3 linux/amd64\n", nil
+		}))
+		require.NoError(t, err)
+		require.True(t, version.Equal(semver.MustParse("1.21.3")))
+	})
+	t.Run("error on malformed output", func(t *testing.T) {
+		_, err := gocli.SemverWith(ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+			return "not a go version", nil
+		}))
+		require.ErrorIs(t, err, gocli.ErrMalformedGoVersionOutput)
+	})
+}
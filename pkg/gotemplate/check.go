@@ -0,0 +1,437 @@
+package gotemplate
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	ownexec "github.com/schwarzit/go-template/pkg/exec"
+)
+
+var (
+	ErrCheckFailed  = errors.New("template check failed")
+	ErrUnknownCheck = errors.New("unknown check")
+
+	// leftoverPattern matches template actions and missing values that ended up in a generated file.
+	// Only actions starting like go/template's own ones are matched since e.g. Go composite literals contain "{{" as well.
+	leftoverPattern = regexp.MustCompile(
+		`(\{\{|\[\[)-?\s*(\.|if\b|else\b|end\b|range\b|with\b|template\b|define\b|block\b)|<no value>`,
+	)
+)
+
+// TemplateCheck verifies a project that has been generated by `gt template check`.
+type TemplateCheck interface {
+	Check(ctx context.Context, projectDir string) error
+}
+
+type TemplateCheckFunc func(ctx context.Context, projectDir string) error
+
+func (f TemplateCheckFunc) Check(ctx context.Context, projectDir string) error {
+	return f(ctx, projectDir)
+}
+
+// TemplateChecks returns the names of all checks that can be run by CheckTemplate in the order they are run.
+func TemplateChecks() []string {
+	return []string{"leftover", "yaml", "makefile", "build", "vet"}
+}
+
+// CheckTemplateOptions configures which option combinations are generated by CheckTemplate
+// and how the generated projects are checked.
+type CheckTemplateOptions struct {
+	// Checks contains the names of the checks to run, all of TemplateChecks if empty.
+	Checks []string
+	// Reduced varies one category at a time while all others keep their defaults.
+	// By default the values of all categories are combined with each other.
+	Reduced bool
+	// Parallel is the number of combinations that are generated and checked at the same time.
+	Parallel int
+}
+
+// Validate validates that all checks are known.
+func (opts *CheckTemplateOptions) Validate() error {
+	for _, name := range opts.Checks {
+		if !contains(TemplateChecks(), name) {
+			return errors.Wrapf(ErrUnknownCheck, "%q, must be one of %v", name, TemplateChecks())
+		}
+	}
+
+	return nil
+}
+
+type checkResult struct {
+	values *OptionValues
+	err    error
+}
+
+// CheckTemplate generates a project for every combination of the extension options and
+// runs the configured checks on it. The result of every combination is printed and
+// ErrCheckFailed is returned if any of them failed.
+func (gt *GT) CheckTemplate(ctx context.Context, opts *CheckTemplateOptions) error {
+	checkNames := opts.Checks
+	if len(checkNames) == 0 {
+		checkNames = TemplateChecks()
+	}
+
+	combinations := gt.Options.combinations(opts.Reduced)
+	gt.printProgressf("Checking %d option combinations with %s...", len(combinations), strings.Join(checkNames, ", "))
+
+	parallel := opts.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]checkResult, len(combinations))
+	semaphore := make(chan struct{}, parallel)
+
+	var wg sync.WaitGroup
+	for i, values := range combinations {
+		wg.Add(1)
+		go func(i int, values *OptionValues) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = checkResult{values: values, err: gt.checkCombination(ctx, values, checkNames)}
+		}(i, values)
+	}
+
+	wg.Wait()
+
+	var failed int
+	for _, result := range results {
+		if result.err == nil {
+			gt.printf("%s %s\n", gt.cyanStyler().Styled("ok  "), gt.Options.describe(result.values))
+			continue
+		}
+
+		failed++
+		gt.printf("%s %s\n", gt.yellowStyler().Bold().Styled("FAIL"), gt.Options.describe(result.values))
+		gt.printf("\t%s\n", strings.ReplaceAll(result.err.Error(), "\n", "\n\t"))
+	}
+
+	if failed > 0 {
+		return errors.Wrapf(ErrCheckFailed, "%d of %d combinations failed", failed, len(combinations))
+	}
+
+	return nil
+}
+
+// checkCombination generates a project for values into a temporary directory and runs all checks on it.
+func (gt *GT) checkCombination(ctx context.Context, values *OptionValues, checkNames []string) error {
+	outputDir, err := os.MkdirTemp("", "gt-check-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(outputDir)
+
+	// the generation's output would be interleaved for parallel checks
+	// and is not of interest as long as the checks pass
+	quiet := &GT{
+		Streams:   Streams{Out: io.Discard, Err: io.Discard},
+		CmdRunner: gt.CmdRunner,
+		Template:  gt.Template,
+		Options:   gt.Options,
+		FuncMap:   gt.FuncMap,
+	}
+
+	// failing initialization commands are only printed as warnings by default, which are discarded here
+	err = quiet.InitNewProject(ctx, &NewRepositoryOptions{OutputDir: outputDir, OptionValues: values, FailOnInitErrors: true})
+	if err != nil {
+		return errors.Wrap(err, "generating project")
+	}
+
+	projectDir := filepath.Join(outputDir, values.Base["projectSlug"].(string))
+	checks := gt.templateChecks()
+
+	var errs []error
+	for _, name := range checkNames {
+		if err := checks[name].Check(ctx, projectDir); err != nil {
+			errs = append(errs, errors.Wrap(err, name))
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
+
+func (gt *GT) templateChecks() map[string]TemplateCheck {
+	runner := gt.CmdRunner
+	if runner == nil {
+		runner = ownexec.NewExecCmdRunner()
+	}
+
+	return map[string]TemplateCheck{
+		"leftover": TemplateCheckFunc(checkLeftovers),
+		"yaml":     TemplateCheckFunc(checkYAML),
+		"makefile": TemplateCheckFunc(func(ctx context.Context, projectDir string) error {
+			return checkMakefile(ctx, runner, projectDir)
+		}),
+		"build": commandCheck(runner, "go", "build", "./..."),
+		"vet":   commandCheck(runner, "go", "vet", "./..."),
+	}
+}
+
+// commandCheck returns a TemplateCheck that runs the command in the project.
+func commandCheck(runner ownexec.CmdRunner, name string, args ...string) TemplateCheck {
+	return TemplateCheckFunc(func(ctx context.Context, projectDir string) error {
+		cg := ownexec.CommandGroup{
+			Commands:  []*exec.Cmd{exec.Command(name, args...)},
+			TargetDir: projectDir,
+		}
+
+		return cg.RunWithContext(ctx, runner)
+	})
+}
+
+// checkLeftovers makes sure that no template actions or missing values ended up in the generated files.
+func checkLeftovers(_ context.Context, projectDir string) error {
+	var leftovers []string
+
+	err := walkProjectFiles(projectDir, func(relPath string, content []byte) error {
+		for i, line := range bytes.Split(content, []byte("\n")) {
+			if leftoverPattern.Match(line) {
+				leftovers = append(leftovers, fmt.Sprintf("%s:%d: %s", relPath, i+1, bytes.TrimSpace(line)))
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(leftovers) > 0 {
+		return fmt.Errorf("unrendered template code:\n%s", strings.Join(leftovers, "\n"))
+	}
+
+	return nil
+}
+
+// checkYAML makes sure that all YAML files of the project can be parsed.
+func checkYAML(_ context.Context, projectDir string) error {
+	var errs []error
+
+	err := walkProjectFiles(projectDir, func(relPath string, content []byte) error {
+		if ext := filepath.Ext(relPath); ext != ".yml" && ext != ".yaml" {
+			return nil
+		}
+
+		if err := validateYAML(content); err != nil {
+			errs = append(errs, errors.Wrap(err, relPath))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return stderrors.Join(errs...)
+}
+
+// checkMakefile makes sure that the Makefile of the project, if there is any, can be parsed by make.
+func checkMakefile(ctx context.Context, runner ownexec.CmdRunner, projectDir string) error {
+	if !exists(filepath.Join(projectDir, "Makefile")) {
+		return nil
+	}
+
+	// in question mode nothing is executed and make exits with 1 if the target is not up to date
+	// and with 2 on errors like syntax errors
+	err := commandCheck(runner, "make", "--question", "--no-print-directory").Check(ctx, projectDir)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return nil
+	}
+
+	return err
+}
+
+// walkProjectFiles calls fn with the content of every regular file of the project outside of the .git folder.
+func walkProjectFiles(projectDir string, fn func(relPath string, content []byte) error) error {
+	return filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(projectDir, path)
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return fn(filepath.ToSlash(relPath), content)
+	})
+}
+
+// combinations enumerates the option space of the extensions.
+// Options that are not displayed for a combination keep their default, all others take every
+// value of their choices or both values if they are bools. Options of the base keep their defaults.
+// If reduced is true only one category is varied at a time.
+func (o *Options) combinations(reduced bool) []*OptionValues {
+	base := NewOptionValues()
+	for i := range o.Base {
+		base.Base[o.Base[i].Name()] = o.Base[i].Default(base)
+	}
+
+	var combinations []*OptionValues
+	for varied := range o.Extensions {
+		variants := []*OptionValues{base}
+		for i, category := range o.Extensions {
+			var next []*OptionValues
+			for _, values := range variants {
+				next = append(next, expandCategory(category, values, !reduced || i == varied)...)
+			}
+			variants = next
+		}
+
+		for _, variant := range variants {
+			if !containsValues(combinations, variant) {
+				combinations = append(combinations, variant)
+			}
+		}
+
+		if !reduced {
+			break
+		}
+	}
+
+	if len(combinations) == 0 {
+		return []*OptionValues{base}
+	}
+
+	return combinations
+}
+
+// expandCategory sets the options of category based on values.
+// If vary is true a copy of values is returned for every possible combination, otherwise the defaults are used.
+func expandCategory(category Category, values *OptionValues, vary bool) []*OptionValues {
+	initial := values.clone()
+	initial.Extensions[category.Name] = OptionNameToValue{}
+
+	expanded := []*OptionValues{initial}
+	for i := range category.Options {
+		option := &category.Options[i]
+
+		var next []*OptionValues
+		for _, current := range expanded {
+			candidates := []interface{}{option.Default(current)}
+			if vary && option.ShouldDisplay(current) {
+				candidates = option.candidates(current)
+			}
+
+			for _, candidate := range candidates {
+				candidateValues := current.clone()
+				candidateValues.Extensions[category.Name][option.Name()] = candidate
+				next = append(next, candidateValues)
+			}
+		}
+		expanded = next
+	}
+
+	return expanded
+}
+
+// candidates returns all values of the option that are of interest when checking the template.
+func (s *Option) candidates(currentValues *OptionValues) []interface{} {
+	if len(s.choices) > 0 {
+		values := make([]interface{}, 0, len(s.choices))
+		for _, choice := range s.choices {
+			values = append(values, choice.Value)
+		}
+
+		return values
+	}
+
+	defaultValue := s.Default(currentValues)
+	if _, ok := defaultValue.(bool); ok {
+		return []interface{}{false, true}
+	}
+
+	return []interface{}{defaultValue}
+}
+
+// describe lists the values of all extension options that are bools or restricted to choices.
+func (o *Options) describe(values *OptionValues) string {
+	var parts []string
+	for _, category := range o.Extensions {
+		for i := range category.Options {
+			option := &category.Options[i]
+
+			value, ok := values.Extensions[category.Name][option.Name()]
+			if !ok {
+				continue
+			}
+
+			if _, isBool := value.(bool); isBool || len(option.Choices()) > 0 {
+				parts = append(parts, fmt.Sprintf("%s.%s=%v", category.Name, option.Name(), value))
+			}
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (ov *OptionValues) clone() *OptionValues {
+	clone := NewOptionValues()
+	for name, value := range ov.Base {
+		clone.Base[name] = value
+	}
+
+	for category, values := range ov.Extensions {
+		clone.Extensions[category] = OptionNameToValue{}
+		for name, value := range values {
+			clone.Extensions[category][name] = value
+		}
+	}
+
+	return clone
+}
+
+func containsValues(list []*OptionValues, values *OptionValues) bool {
+	for _, other := range list {
+		if reflect.DeepEqual(other, values) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, other := range list {
+		if other == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,139 @@
+package gotemplate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ownexec "github.com/schwarzit/go-template/pkg/exec"
+)
+
+func TestOptions_combinations(t *testing.T) {
+	options := NewOptions(nil)
+
+	t.Run("one category at a time", func(t *testing.T) {
+		combinations := options.combinations(true)
+
+		// 9 licenses + 3 other CI providers + 2 more gRPC variants
+		require.Len(t, combinations, 14)
+		for _, values := range combinations {
+			require.Equal(t, "awesome-project", values.Base["projectSlug"])
+		}
+
+		assert.Equal(t, 0, combinations[0].Extensions["openSource"]["license"])
+		assert.Equal(t, 1, combinations[0].Extensions["ci"]["provider"])
+		assert.Equal(t, false, combinations[0].Extensions["grpc"]["base"])
+	})
+
+	t.Run("all combinations respect ShouldDisplay", func(t *testing.T) {
+		combinations := options.combinations(false)
+
+		// gRPC gateway can only be enabled with gRPC base, so there are 3 variants instead of 4
+		require.Len(t, combinations, 9*4*3)
+		for _, values := range combinations {
+			if !values.Extensions["grpc"]["base"].(bool) {
+				require.Equal(t, false, values.Extensions["grpc"]["grpcGateway"])
+			}
+		}
+	})
+}
+
+func Test_checkLeftovers(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		leftover bool
+	}{
+		{name: "plain text", content: "some text", leftover: false},
+		{name: "composite literal", content: "tests := []test{{name: \"a\"}}", leftover: false},
+		{name: "github expression", content: "if: ${{ github.ref == 'main' }}", leftover: false},
+		{name: "bash test", content: "[[ -f Makefile ]]", leftover: false},
+		{name: "field", content: "name: {{ .Base.projectName }}", leftover: true},
+		{name: "trimmed action", content: "{{- if .Extensions.grpc.base }}", leftover: true},
+		{name: "custom delims", content: "[[ end ]]", leftover: true},
+		{name: "missing value", content: "name: <no value>", leftover: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte(test.content), permissionRW))
+
+			err := checkLeftovers(context.Background(), dir)
+			if test.leftover {
+				require.ErrorContains(t, err, "file.txt:1")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_checkYAML(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "valid.yml"), []byte("a: 1\n---\nb: 2\n"), permissionRW))
+	require.NoError(t, checkYAML(context.Background(), dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.yaml"), []byte("a: [1\n"), permissionRW))
+	require.ErrorContains(t, checkYAML(context.Background(), dir), "invalid.yaml")
+}
+
+func TestGT_CheckTemplate(t *testing.T) {
+	out := &bytes.Buffer{}
+	gt := GT{
+		Streams: Streams{Out: out, Err: &bytes.Buffer{}},
+		// initialization commands are not of interest here
+		CmdRunner: ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+			return "go version go1.21.0 linux/amd64", nil
+		}),
+		Template: fstest.MapFS{
+			"README.md": {Data: []byte("# {{ .Base.projectName }}\n")},
+			// leaves a template action in the project if gRPC is enabled
+			"api.txt": {Data: []byte(`{{ if .Extensions.grpc.base }}{{ "{{ .Base.appName }}" }}{{ end }}`)},
+		},
+		Options: NewOptions(nil),
+	}
+
+	err := gt.CheckTemplate(context.Background(), &CheckTemplateOptions{Checks: []string{"leftover", "yaml"}, Reduced: true, Parallel: 4})
+	require.ErrorIs(t, err, ErrCheckFailed)
+	require.ErrorContains(t, err, "2 of 14 combinations failed")
+
+	assert.Contains(t, out.String(), "FAIL openSource.license=1 ci.provider=1 grpc.base=true grpc.grpcGateway=false")
+	assert.Contains(t, out.String(), "api.txt:1: {{ .Base.appName }}")
+}
+
+func TestGT_CheckTemplate_initErrors(t *testing.T) {
+	out := &bytes.Buffer{}
+	gt := GT{
+		Streams: Streams{Out: out, Err: &bytes.Buffer{}},
+		CmdRunner: ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+			if cmd.Args[len(cmd.Args)-1] == "tidy" {
+				return "", &ownexec.ErrWithStderr{Wrapped: errors.New("exit status 1"), Args: cmd.Args, StdErr: []byte("missing module")}
+			}
+
+			return "go version go1.21.0 linux/amd64", nil
+		}),
+		Template: fstest.MapFS{"README.md": {Data: []byte("# {{ .Base.projectName }}\n")}},
+		Options:  NewOptions(nil),
+	}
+
+	// all categories are combined by default
+	err := gt.CheckTemplate(context.Background(), &CheckTemplateOptions{Checks: []string{"leftover"}, Parallel: 4})
+	require.ErrorContains(t, err, "108 of 108 combinations failed")
+	assert.Contains(t, out.String(), "initializing project")
+	assert.Contains(t, out.String(), "missing module")
+}
+
+func TestCheckTemplateOptions_Validate(t *testing.T) {
+	require.NoError(t, (&CheckTemplateOptions{}).Validate())
+	require.NoError(t, (&CheckTemplateOptions{Checks: TemplateChecks()}).Validate())
+	require.ErrorIs(t, (&CheckTemplateOptions{Checks: []string{"lint"}}).Validate(), ErrUnknownCheck)
+}
@@ -0,0 +1,89 @@
+package gotemplate
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// coerceFileValue converts value read from a values file to the type of the option's default value.
+// Compatible scalars are converted, e.g. the quoted number "1" to 1 or "yes" to true.
+// An ErrTypeMismatch is returned for all other values of another type.
+// Unset (nil) values are returned as is as well as values of options without a default to take the type from.
+func coerceFileValue(key string, value, defaultVal interface{}) (interface{}, error) {
+	if value == nil || defaultVal == nil || reflect.TypeOf(value) == reflect.TypeOf(defaultVal) {
+		return value, nil
+	}
+
+	var (
+		coerced interface{}
+		ok      bool
+	)
+
+	switch defaultVal.(type) {
+	case int:
+		coerced, ok = coerceInt(value)
+	case bool:
+		coerced, ok = coerceBool(value)
+	case string:
+		coerced, ok = coerceString(value)
+	}
+
+	if !ok {
+		return nil, errors.Wrap(&ErrTypeMismatch{
+			Expected: reflect.TypeOf(defaultVal).String(),
+			Actual:   reflect.TypeOf(value).String(),
+		}, key)
+	}
+
+	return coerced, nil
+}
+
+func coerceInt(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		i, err := strconv.Atoi(strings.TrimSpace(v))
+		return i, err == nil
+	case float64:
+		// e.g. 1.0
+		return int(v), v == math.Trunc(v)
+	default:
+		return nil, false
+	}
+}
+
+func coerceBool(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "yes", "y", "on", "1":
+			return true, true
+		case "false", "no", "n", "off", "0":
+			return false, true
+		}
+	case int:
+		switch v {
+		case 1:
+			return true, true
+		case 0:
+			return false, true
+		}
+	}
+
+	return nil, false
+}
+
+func coerceString(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		// floats are not converted since their formatting might differ from the file, e.g. 1.20
+		return nil, false
+	}
+}
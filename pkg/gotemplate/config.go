@@ -0,0 +1,276 @@
+package gotemplate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/schwarzit/go-template/pkg/repos"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// orgConfigEnv points to the config file of an organisation.
+	orgConfigEnv   = "GT_ORG_CONFIG"
+	configFileName = "config.yml"
+)
+
+var ErrUnknownOption = errors.New("unknown option")
+
+// Config is a user or organisation level configuration of gt.
+type Config struct {
+	// Defaults replace the default values of the options referenced by their keys (see Relation.Option).
+	// Strings can be templates using the values of earlier options, e.g. "gitlab.company.com/team/{{ .Base.projectSlug }}".
+	// Options restricted to choices can also be set to the description of a choice, e.g. "gitlab".
+	Defaults map[string]interface{} `yaml:"defaults"`
+	// Releases configures where gt looks for newer releases of itself, e.g. a mirror on GitLab or a Go module proxy.
+	Releases *repos.SourceConfig `yaml:"releases,omitempty"`
+}
+
+// UserConfigFile returns the path of the user's config file "gt/config.yml" in $XDG_CONFIG_HOME or the OS specific config dir.
+func UserConfigFile() (string, error) {
+	dir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, configFileName), nil
+}
+
+// DefaultConfigFiles returns the organisation's config file set in $GT_ORG_CONFIG and the user's config file.
+// The user's config takes precedence.
+func DefaultConfigFiles() []string {
+	var files []string
+
+	if file := os.Getenv(orgConfigEnv); file != "" {
+		files = append(files, file)
+	}
+
+	if file, err := UserConfigFile(); err == nil {
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// LoadConfig reads the config in file. If it doesn't exist an empty config is returned.
+func LoadConfig(file string) (*Config, error) {
+	config := &Config{Defaults: map[string]interface{}{}}
+
+	fileBytes, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(fileBytes, config); err != nil {
+		return nil, errors.Wrapf(err, "config %s", file)
+	}
+
+	if config.Defaults == nil {
+		config.Defaults = map[string]interface{}{}
+	}
+
+	return config, nil
+}
+
+// Save writes the config to file and creates its directory if needed.
+func (c *Config) Save(file string) error {
+	if err := os.MkdirAll(filepath.Dir(file), permissionRWX); err != nil {
+		return err
+	}
+
+	configBytes, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, configBytes, permissionRW)
+}
+
+// ApplyConfig overrides the defaults of gt.Options with the ones of all gt.ConfigFiles in order.
+// Defaults of unknown options are reported like unknown keys in values files.
+func (gt *GT) ApplyConfig() error {
+	for _, file := range gt.ConfigFiles {
+		config, err := LoadConfig(file)
+		if err != nil {
+			return err
+		}
+
+		var unknown []string
+
+		for _, key := range sortedKeys(config.Defaults) {
+			err := gt.Options.SetDefault(key, config.Defaults[key])
+			if errors.Is(err, ErrUnknownOption) {
+				unknown = append(unknown, fmt.Sprintf("config %s: %s", file, describeUnknown(key, gt.Options.keys())))
+				continue
+			}
+
+			if err != nil {
+				return errors.Wrapf(err, "config %s", file)
+			}
+		}
+
+		if err := gt.reportUnknownKeys(unknown); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetDefault replaces the default value of the option referenced by key (see Relation.Option) with value.
+// Values are coerced to the type of the option's values and validated.
+// See Config.Defaults for the values that are supported.
+func (o *Options) SetDefault(key string, value interface{}) error {
+	option := o.lookup(key)
+	if option == nil {
+		return errors.Wrap(ErrUnknownOption, key)
+	}
+
+	// templates are checked first since an earlier config might have replaced a dynamic default with a static one
+	if str, ok := value.(string); ok && strings.Contains(str, "{{") {
+		tmpl, err := template.New(key).Funcs(FuncMap()).Parse(str)
+		if err != nil {
+			return errors.Wrap(ErrMalformedInput, fmt.Sprintf("%s: %s", key, err.Error()))
+		}
+
+		option.defaultValue = DynamicValue(func(vals *OptionValues) interface{} {
+			var rendered strings.Builder
+			if err := tmpl.Execute(&rendered, vals); err != nil {
+				// the value is validated like any other value afterwards
+				return str
+			}
+
+			return rendered.String()
+		})
+
+		return nil
+	}
+
+	value = option.choiceByDescription(value)
+
+	// dynamic defaults aren't evaluated since they might depend on other values or run commands,
+	// values are coerced to the type the option's default would have instead
+	value, err := coerceFileValue(key, value, option.sampleValue())
+	if err != nil {
+		return err
+	}
+
+	if err := option.Validate(value); err != nil {
+		return errors.Wrap(ErrMalformedInput, fmt.Sprintf("%s: %s", key, err.Error()))
+	}
+
+	option.defaultValue = StaticValue(value)
+
+	return nil
+}
+
+// choiceByDescription returns the value of the choice described by value (ignoring case), e.g. 2 for "gitlab".
+// If there is no such choice value is returned.
+func (s *Option) choiceByDescription(value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	for _, choice := range s.choices {
+		if strings.EqualFold(choice.Description, str) {
+			return choice.Value
+		}
+	}
+
+	return value
+}
+
+// SetConfigDefault validates value as default of the option referenced by key and stores it in the config file.
+func (gt *GT) SetConfigDefault(file, key, value string) error {
+	if err := gt.Options.SetDefault(key, value); err != nil {
+		if errors.Is(err, ErrUnknownOption) {
+			return errors.Wrap(ErrUnknownOption, describeUnknown(key, gt.Options.keys()))
+		}
+
+		return err
+	}
+
+	config, err := LoadConfig(file)
+	if err != nil {
+		return err
+	}
+
+	config.Defaults[key] = value
+	// values of static defaults have been coerced, e.g. "gitlab" to 2
+	if static, ok := gt.Options.lookup(key).defaultValue.(*Value); ok {
+		config.Defaults[key] = static.v
+	}
+
+	return config.Save(file)
+}
+
+// PrintConfig prints the default of the option referenced by key that is set in gt.ConfigFiles
+// or all defaults together with the file they are set in if key is empty.
+func (gt *GT) PrintConfig(key string) error {
+	if key != "" && !gt.Options.has(key) {
+		return errors.Wrap(ErrUnknownOption, describeUnknown(key, gt.Options.keys()))
+	}
+
+	defaults := map[string]interface{}{}
+	sources := map[string]string{}
+
+	for _, file := range gt.ConfigFiles {
+		config, err := LoadConfig(file)
+		if err != nil {
+			return err
+		}
+
+		for k, value := range config.Defaults {
+			defaults[k], sources[k] = value, file
+		}
+	}
+
+	if key != "" {
+		if value, ok := defaults[key]; ok {
+			gt.printf("%v\n", value)
+		}
+
+		return nil
+	}
+
+	for _, k := range sortedKeys(defaults) {
+		gt.printf("%s: %v (%s)\n", k, defaults[k], sources[k])
+	}
+
+	return nil
+}
+
+// sampleValue returns a value of the option's type without computing a dynamic default.
+// It is taken from the choices, the example, the static default or the fallback of a ContextValue, options with other dynamic defaults are strings.
+func (s *Option) sampleValue() interface{} {
+	var value interface{}
+
+	switch {
+	case len(s.choices) > 0:
+		value = s.choices[0].Value
+	case s.example != nil:
+		value = s.example
+	default:
+		switch defaultValue := s.defaultValue.(type) {
+		case *Value:
+			value = defaultValue.v
+		case *ContextValue:
+			value = defaultValue.Fallback
+		}
+	}
+
+	if value == nil {
+		return ""
+	}
+
+	return value
+}
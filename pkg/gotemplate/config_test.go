@@ -0,0 +1,84 @@
+package gotemplate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGT_ApplyConfig(t *testing.T) {
+	dir := t.TempDir()
+	orgConfig, userConfig := filepath.Join(dir, "org.yml"), filepath.Join(dir, "user.yml")
+
+	require.NoError(t, os.WriteFile(orgConfig, []byte(`defaults:
+  moduleName: gitlab.company.com/team/{{ .Base.projectSlug }}
+  ci.provider: gitlab
+  openSource.license: 2
+`), permissionRW))
+	require.NoError(t, os.WriteFile(userConfig, []byte(`defaults:
+  openSource.license: "0"
+  openSource.author: Jane Doe
+  grpc.bas: true
+`), permissionRW))
+
+	out := &bytes.Buffer{}
+	gt := &GT{Streams: Streams{Out: out, Err: out}, Options: NewOptions(nil), ConfigFiles: []string{orgConfig, userConfig}}
+	require.NoError(t, gt.ApplyConfig())
+
+	values := &OptionValues{Base: OptionNameToValue{"projectSlug": "my-project"}}
+	assert.Equal(t, "gitlab.company.com/team/my-project", gt.Options.lookup("moduleName").Default(values))
+	assert.Equal(t, 2, gt.Options.lookup("ci.provider").Default(values))
+	// the user's config takes precedence
+	assert.Equal(t, 0, gt.Options.lookup("openSource.license").Default(values))
+	assert.Equal(t, "Jane Doe", gt.Options.lookup("openSource.author").Default(values))
+	assert.Contains(t, out.String(), `config `+userConfig+`: "grpc.bas" (did you mean "grpc.base"?): unknown key, it is ignored`)
+
+	t.Run("error on invalid default", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(userConfig, []byte("defaults:\n  ci.provider: jenkins\n"), permissionRW))
+
+		gt := &GT{Options: NewOptions(nil), ConfigFiles: []string{userConfig}}
+		err := gt.ApplyConfig()
+
+		var errTypeMismatch *ErrTypeMismatch
+		require.ErrorAs(t, err, &errTypeMismatch)
+		require.ErrorContains(t, err, "config "+userConfig+": ci.provider")
+	})
+}
+
+func TestGT_SetConfigDefault(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "gt", configFileName)
+	out := &bytes.Buffer{}
+	gt := &GT{Streams: Streams{Out: out}, Options: NewOptions(nil), ConfigFiles: []string{file}}
+
+	require.NoError(t, gt.SetConfigDefault(file, "ci.provider", "Azure DevOps"))
+	require.NoError(t, gt.SetConfigDefault(file, "openSource.codeowner", "team@company.com"))
+	require.ErrorIs(t, gt.SetConfigDefault(file, "ci.providr", "1"), ErrUnknownOption)
+
+	var errTypeMismatch *ErrTypeMismatch
+	require.ErrorAs(t, gt.SetConfigDefault(file, "grpc.base", "maybe"), &errTypeMismatch)
+
+	config, err := LoadConfig(file)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"ci.provider": 3, "openSource.codeowner": "team@company.com"}, config.Defaults)
+
+	require.NoError(t, gt.PrintConfig("ci.provider"))
+	require.NoError(t, gt.PrintConfig(""))
+	assert.Equal(t, "3\nci.provider: 3 ("+file+")\nopenSource.codeowner: team@company.com ("+file+")\n", out.String())
+}
+
+func TestOptions_SetDefault_dynamicDefaults(t *testing.T) {
+	options := NewOptions(nil)
+
+	require.ErrorIs(t, options.SetDefault("projectSlug", "Bad Slug"), ErrMalformedInput)
+
+	// ints in YAML files are coerced to the type of the option
+	require.NoError(t, options.SetDefault("projectSlug", 1))
+	assert.Equal(t, "1", options.lookup("projectSlug").Default(nil))
+
+	require.NoError(t, options.SetDefault("openSource.author", 42))
+	assert.Equal(t, "42", options.lookup("openSource.author").Default(nil))
+}
@@ -0,0 +1,150 @@
+package gotemplate
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// optionReference matches the options referenced in include conditions, e.g. ".Extensions.grpc.base".
+var optionReference = regexp.MustCompile(`\.(Base|Extensions\.(\w+))\.(\w+)`) //nolint:gochecknoglobals // compiled regex
+
+// FileRule is an include rule of the template's rules file, see templateRules.Include.
+type FileRule struct {
+	// Glob matches the files and directories the rule applies to.
+	Glob string
+	// Condition is the template pipeline deciding whether they are generated, e.g. "ne .Extensions.openSource.license 0".
+	Condition string
+}
+
+// Type returns the type of the option's values, e.g. "bool".
+// It is derived from the static default, the choices or the example and "string" otherwise.
+func (s *Option) Type() string {
+	return reflect.TypeOf(s.sampleValue()).String()
+}
+
+// StaticDefault returns the default value if it doesn't depend on any input.
+func (s *Option) StaticDefault() (interface{}, bool) {
+	static, ok := s.defaultValue.(*Value)
+	if !ok {
+		return nil, false
+	}
+
+	return static.v, true
+}
+
+// DefaultDescription describes how the default value is computed if it is dynamic.
+func (s *Option) DefaultDescription() string {
+	if s.defaultDescription != "" {
+		return s.defaultDescription
+	}
+
+	return "computed from earlier values"
+}
+
+// AllowedValues describes the values the option accepts besides its choices, empty if it accepts any value of its type.
+func (s *Option) AllowedValues() string {
+	if validator, ok := s.validator.(DescribedValidator); ok {
+		return validator.Describe()
+	}
+
+	if s.Type() == "bool" {
+		return "true, false"
+	}
+
+	return ""
+}
+
+// ShownIf describes the conditions that decide whether the option is shown, empty if it is always shown.
+func (s *Option) ShownIf() []string {
+	conditions := make([]string, 0, len(s.requires))
+	for _, relation := range s.requires {
+		conditions = append(conditions, relation.String())
+	}
+
+	if s.shouldDisplay != nil {
+		conditions = append(conditions, "a custom condition on earlier values")
+	}
+
+	return conditions
+}
+
+// ConflictsWith describes the relations that must not hold if the option is enabled.
+func (s *Option) ConflictsWith() []string {
+	conflicts := make([]string, 0, len(s.conflictsWith))
+	for _, relation := range s.conflictsWith {
+		conflicts = append(conflicts, relation.String())
+	}
+
+	return conflicts
+}
+
+// Example returns the value of the option that is used in examples.
+// It is the example value if there is one and the default (calculated with currentValues) otherwise.
+func (s *Option) Example(currentValues *OptionValues) interface{} {
+	if s.example != nil {
+		return s.example
+	}
+
+	return s.Default(currentValues)
+}
+
+// String describes the relation, e.g. "openSource.license is one of 1, 2".
+func (r Relation) String() string {
+	return fmt.Sprintf("%s is %s", r.Option, r.expectation())
+}
+
+// ExampleValues returns the example values of all options, e.g. for an example values file.
+// Other than in generated projects the values of hidden options are included as well.
+func (o *Options) ExampleValues() *OptionValues {
+	values := NewOptionValues()
+
+	for i := range o.Base {
+		values.Base[o.Base[i].Name()] = o.Base[i].Example(values)
+	}
+
+	for _, category := range o.Extensions {
+		values.Extensions[category.Name] = OptionNameToValue{}
+
+		for i := range category.Options {
+			values.Extensions[category.Name][category.Options[i].Name()] = category.Options[i].Example(values)
+		}
+	}
+
+	return values
+}
+
+// FileRules returns the include rules of the template by the keys of the options their conditions reference.
+// Those options decide whether the matching files are part of the generated project.
+func (gt *GT) FileRules() (map[string][]FileRule, error) {
+	rules, err := loadTemplateRules(gt.template(), ".")
+	if err != nil {
+		return nil, err
+	}
+
+	fileRules := map[string][]FileRule{}
+
+	for _, rule := range rules.Include {
+		var keys []string
+
+		for _, match := range optionReference.FindAllStringSubmatch(rule.If, -1) {
+			key := optionKey(match[2], match[3])
+			if !gt.Options.has(key) {
+				return nil, errors.Wrapf(ErrMalformedInput, "glob %q: condition references unknown option %q", rule.Glob, key)
+			}
+
+			if !contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+
+		for _, key := range keys {
+			fileRules[key] = append(fileRules[key], FileRule{Glob: rule.Glob, Condition: strings.TrimSpace(rule.If)})
+		}
+	}
+
+	return fileRules, nil
+}
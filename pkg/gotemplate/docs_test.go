@@ -0,0 +1,112 @@
+package gotemplate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOption_Type(t *testing.T) {
+	tests := []struct {
+		option Option
+		want   string
+	}{
+		{option: NewOption("a", "", StaticValue(false)), want: "bool"},
+		{option: NewOption("a", "", DynamicValue(nil), WithChoices(Choice{Value: 1})), want: "int"},
+		{option: NewOption("a", "", DynamicValue(nil), WithExample(1)), want: "int"},
+		{option: NewOption("a", "", DynamicValue(nil)), want: "string"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, test.option.Type())
+	}
+}
+
+func TestOption_AllowedValues(t *testing.T) {
+	tests := []struct {
+		option Option
+		want   string
+	}{
+		{
+			option: NewOption("a", "", StaticValue(""), WithValidator(PatternValidator{Pattern: `^[\S]+$`, Description: "no whitespaces"})),
+			want:   `no whitespaces (pattern: ^[\S]+$)`,
+		},
+		{option: NewOption("a", "", StaticValue(1), WithValidator(ValueRange{Min: 1, Max: 3})), want: "1 to 3"},
+		{option: NewOption("a", "", StaticValue(false)), want: "true, false"},
+		// validators that can't describe themselves
+		{option: NewOption("a", "", StaticValue(""), WithValidator(RegexValidator(".*", "anything"))), want: ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, test.option.AllowedValues())
+	}
+}
+
+func TestOption_ShownIf(t *testing.T) {
+	option := NewOption("a", "", StaticValue(""),
+		WithRequires(Relation{Option: "grpc.base"}, Relation{Option: "license", OneOf: []interface{}{1, 2}}),
+		WithShouldDisplay(BoolValue(true)),
+	)
+	assert.Equal(t, []string{"grpc.base is enabled", "license is one of 1, 2", "a custom condition on earlier values"}, option.ShownIf())
+
+	option = NewOption("a", "", StaticValue(""))
+	assert.Empty(t, option.ShownIf())
+}
+
+func TestOptions_ExampleValues(t *testing.T) {
+	options := &Options{
+		Base: []Option{
+			NewOption("name", "", StaticValue("Name")),
+			NewOption("slug", "", DynamicValue(func(vals *OptionValues) interface{} {
+				return vals.Base["name"].(string) + "-slug"
+			})),
+		},
+		Extensions: []Category{
+			{Name: "cat", Options: []Option{NewOption("author", "", DynamicValue(nil), WithExample("Marty"))}},
+		},
+	}
+
+	assert.Equal(t, &OptionValues{
+		Base:       OptionNameToValue{"name": "Name", "slug": "Name-slug"},
+		Extensions: map[string]OptionNameToValue{"cat": {"author": "Marty"}},
+	}, options.ExampleValues())
+}
+
+func TestGT_FileRules(t *testing.T) {
+	gt := &GT{
+		Options: &Options{
+			Base:       []Option{NewOption("name", "", StaticValue(""))},
+			Extensions: []Category{{Name: "grpc", Options: []Option{NewOption("base", "", StaticValue(false))}}},
+		},
+		Template: fstest.MapFS{rulesFile: &fstest.MapFile{Data: []byte(`include:
+  - glob: api/proto
+    if: and .Extensions.grpc.base (ne .Base.name "") .Extensions.grpc.base
+  - glob: api/openapi.yml
+    if: not .Extensions.grpc.base
+`)}},
+	}
+
+	rules, err := gt.FileRules()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]FileRule{
+		"grpc.base": {
+			{Glob: "api/proto", Condition: `and .Extensions.grpc.base (ne .Base.name "") .Extensions.grpc.base`},
+			{Glob: "api/openapi.yml", Condition: "not .Extensions.grpc.base"},
+		},
+		"name": {{Glob: "api/proto", Condition: `and .Extensions.grpc.base (ne .Base.name "") .Extensions.grpc.base`}},
+	}, rules)
+
+	t.Run("error on unknown option", func(t *testing.T) {
+		gt.Template = fstest.MapFS{rulesFile: &fstest.MapFile{Data: []byte("include:\n  - glob: LICENSE\n    if: .Base.license\n")}}
+		_, err := gt.FileRules()
+		require.ErrorIs(t, err, ErrMalformedInput)
+	})
+
+	t.Run("embedded template", func(t *testing.T) {
+		rules, err := (&GT{Options: NewOptions(nil)}).FileRules()
+		require.NoError(t, err)
+		assert.Contains(t, rules, "openSource.license")
+	})
+}
@@ -0,0 +1,126 @@
+package gotemplate
+
+import (
+	"bytes"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/imports"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidGo   = errors.New("rendered file is no valid Go code")
+	ErrInvalidYAML = errors.New("rendered file is no valid YAML")
+
+	// blockScalarStart matches lines starting a literal or folded block scalar, e.g. "run: |" or "- >-".
+	blockScalarStart = regexp.MustCompile(`(^|:\s+|-\s+)[|>][-+1-9]*\s*(#.*)?$`)
+)
+
+// formatRendered formats the rendered content of the file at filePath depending on its type.
+// Go files are formatted like gofmt does and their imports are grouped like goimports does.
+// YAML files are checked to be valid and runs of blank lines left by conditional blocks are collapsed outside of block scalars.
+// The content of all other files is returned as is.
+func formatRendered(filePath string, content []byte) ([]byte, error) {
+	switch path.Ext(filePath) {
+	case ".go":
+		return formatGo(filePath, content)
+	case ".yml", ".yaml":
+		return normalizeYAML(content)
+	default:
+		return content, nil
+	}
+}
+
+func formatGo(filePath string, content []byte) ([]byte, error) {
+	formatted, err := imports.Process(filePath, content, &imports.Options{
+		// only formatting, imports are neither added nor removed
+		FormatOnly: true,
+		Comments:   true,
+		TabIndent:  true,
+		TabWidth:   8, //nolint:gomnd // gofmt's tab width
+	})
+	if err != nil {
+		// the error already contains the file path and position
+		return nil, errors.Wrap(ErrInvalidGo, err.Error())
+	}
+
+	return formatted, nil
+}
+
+// normalizeYAML collapses runs of blank (or whitespace only) lines that conditional blocks leave behind into one.
+// The content of block scalars (e.g. scripts in CI files) is kept as is since blank lines are part of their value.
+func normalizeYAML(content []byte) ([]byte, error) {
+	var (
+		lines []string
+		// blockIndent is the indentation of the line starting the current block scalar, -1 outside of them
+		blockIndent = -1
+		blankRun    = 0
+	)
+
+	for _, line := range strings.Split(string(content), "\n") {
+		blank := strings.TrimSpace(line) == ""
+
+		if blockIndent >= 0 {
+			if blank || indentation(line) > blockIndent {
+				lines = append(lines, line)
+				continue
+			}
+
+			blockIndent = -1
+		}
+
+		if blank {
+			blankRun++
+			if blankRun == 1 {
+				lines = append(lines, line)
+			} else {
+				lines[len(lines)-1] = ""
+			}
+
+			continue
+		}
+
+		blankRun = 0
+		lines = append(lines, line)
+
+		if blockScalarStart.MatchString(strings.TrimRight(line, " \t")) {
+			blockIndent = indentation(line)
+		}
+	}
+
+	normalized := []byte(strings.Join(lines, "\n"))
+	// trailing blank lines belong to a block scalar at the end of the file
+	if blockIndent < 0 {
+		normalized = append(bytes.TrimRight(normalized, "\n"), '\n')
+	}
+
+	if err := validateYAML(normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+// indentation returns the number of spaces line is indented with.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// validateYAML makes sure that all documents in content can be parsed.
+func validateYAML(content []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var document interface{}
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return errors.Wrap(ErrInvalidYAML, err.Error())
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package gotemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_formatRendered(t *testing.T) {
+	t.Run("formats Go code and groups imports", func(t *testing.T) {
+		rendered := `package main
+
+import (
+	"github.com/user/project/internal/log"
+	"os"
+
+)
+
+
+func main() {
+log.New(os.Getenv("LOG_LEVEL"))
+}
+`
+		formatted, err := formatRendered("cmd/app/main.go", []byte(rendered))
+		require.NoError(t, err)
+		assert.Equal(t, `package main
+
+import (
+	"os"
+
+	"github.com/user/project/internal/log"
+)
+
+func main() {
+	log.New(os.Getenv("LOG_LEVEL"))
+}
+`, string(formatted))
+	})
+
+	t.Run("error on invalid Go code", func(t *testing.T) {
+		_, err := formatRendered("tools.go", []byte("package tools\n\nimport (\n"))
+		require.ErrorIs(t, err, ErrInvalidGo)
+		require.ErrorContains(t, err, "tools.go:3:")
+	})
+
+	t.Run("collapses blank lines in YAML", func(t *testing.T) {
+		formatted, err := formatRendered("buf.gen.yaml", []byte("version: v1\n\n  \n\nplugins: []\n\n\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "version: v1\n\nplugins: []\n", string(formatted))
+	})
+
+	t.Run("keeps blank lines in YAML block scalars", func(t *testing.T) {
+		content := "jobs:\n  test:\n    run: |\n      go test ./...\n\n\n      go vet ./...\n\n\n    env: >-\n      a\n\n\n      b\n\n\n\n  lint:\n    - |\n      one\n\n\n      two\n"
+
+		formatted, err := formatRendered(".gitlab-ci.yml", []byte(content))
+		require.NoError(t, err)
+		assert.Equal(t, "jobs:\n  test:\n    run: |\n      go test ./...\n\n\n      go vet ./...\n\n\n    env: >-\n      a\n\n\n      b\n\n\n\n  lint:\n    - |\n      one\n\n\n      two\n", string(formatted))
+
+		// blank lines after the block scalar are collapsed again
+		formatted, err = formatRendered("ci.yml", []byte("script: |\n  echo\n\n\nkey: value\n\n\n\nother: value\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "script: |\n  echo\n\n\nkey: value\n\nother: value\n", string(formatted))
+	})
+
+	t.Run("error on invalid YAML", func(t *testing.T) {
+		_, err := formatRendered(".gitlab-ci.yml", []byte("stages:\n  - test\n - build\n"))
+		require.ErrorIs(t, err, ErrInvalidYAML)
+		require.ErrorContains(t, err, "yaml: line 2")
+	})
+
+	t.Run("other files are not changed", func(t *testing.T) {
+		formatted, err := formatRendered("README.md", []byte("# Title\n\n\n\ntext  \n"))
+		require.NoError(t, err)
+		assert.Equal(t, "# Title\n\n\n\ntext  \n", string(formatted))
+	})
+}
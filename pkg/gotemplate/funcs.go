@@ -0,0 +1,145 @@
+package gotemplate
+
+import (
+	"go/token"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// maxK8sNameLength is the maximum length of a DNS-1123 label which is used for most names in Kubernetes.
+const maxK8sNameLength = 63
+
+var (
+	nonLowerAlphanumeric  = regexp.MustCompile(`[^a-z0-9]+`)
+	dockerInvalidChars    = regexp.MustCompile(`[^a-z0-9._/-]+`)
+	dockerSeparatorRepeat = regexp.MustCompile(`[._-]{2,}`)
+)
+
+// FuncMap returns the functions that can be used in the template.
+// It contains all of sprig's functions and gt's own helpers for turning values like
+// the appName into valid names for the different tools used in a project.
+func FuncMap() template.FuncMap {
+	funcMap := sprig.TxtFuncMap()
+
+	funcMap["goPackageName"] = goPackageName
+	funcMap["goExportedIdent"] = goExportedIdent
+	funcMap["protoPackage"] = protoPackage
+	funcMap["envVarName"] = envVarName
+	funcMap["dockerImageName"] = dockerImageName
+	funcMap["k8sName"] = k8sName
+
+	return funcMap
+}
+
+// goPackageName converts s into a Go package name consisting of lowercase letters and digits only,
+// e.g. "my-app" becomes "myapp".
+// Names starting with a digit and keywords are prefixed with "pkg".
+func goPackageName(s string) string {
+	name := nonLowerAlphanumeric.ReplaceAllString(strings.ToLower(s), "")
+	if name == "" || startsWithDigit(name) || token.IsKeyword(name) {
+		name = "pkg" + name
+	}
+
+	return name
+}
+
+// goExportedIdent converts s into an exported Go identifier by capitalizing every word,
+// e.g. "my-app" becomes "MyApp".
+// Identifiers starting with a digit are prefixed with "X".
+func goExportedIdent(s string) string {
+	var ident strings.Builder
+	for _, word := range splitWords(s) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		ident.WriteString(string(runes))
+	}
+
+	if ident.Len() == 0 || startsWithDigit(ident.String()) {
+		return "X" + ident.String()
+	}
+
+	return ident.String()
+}
+
+// protoPackage converts s into a protobuf package name, e.g. "my-app" becomes "my_app".
+// Dots are kept to separate the parts of the package, parts starting with a digit are prefixed with "pkg".
+func protoPackage(s string) string {
+	var parts []string
+	for _, part := range strings.Split(s, ".") {
+		part = strings.Join(splitWords(strings.ToLower(part)), "_")
+		if part == "" {
+			continue
+		}
+
+		if startsWithDigit(part) {
+			part = "pkg" + part
+		}
+
+		parts = append(parts, part)
+	}
+
+	if len(parts) == 0 {
+		return "pkg"
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// envVarName converts s into the name of an environment variable, e.g. "my-app" becomes "MY_APP".
+// Names starting with a digit are prefixed with an underscore.
+func envVarName(s string) string {
+	name := strings.Join(splitWords(strings.ToUpper(s)), "_")
+	if name == "" || startsWithDigit(name) {
+		return "_" + name
+	}
+
+	return name
+}
+
+// dockerImageName converts s into a docker image repository name, e.g. "My App" becomes "my-app".
+// Slashes are kept to separate the path components of the repository.
+func dockerImageName(s string) string {
+	components := strings.Split(strings.ToLower(s), "/")
+
+	var valid []string
+	for _, component := range components {
+		component = dockerInvalidChars.ReplaceAllString(component, "-")
+		component = dockerSeparatorRepeat.ReplaceAllStringFunc(component, func(separators string) string {
+			return separators[:1]
+		})
+		// components must start and end with a letter or digit
+		component = strings.Trim(component, "._-")
+
+		if component != "" {
+			valid = append(valid, component)
+		}
+	}
+
+	return strings.Join(valid, "/")
+}
+
+// k8sName converts s into a DNS-1123 label as required for most names of Kubernetes resources,
+// e.g. "My_App" becomes "my-app". Names longer than 63 characters are truncated.
+func k8sName(s string) string {
+	name := strings.Join(splitWords(strings.ToLower(s)), "-")
+	if len(name) > maxK8sNameLength {
+		name = strings.TrimRight(name[:maxK8sNameLength], "-")
+	}
+
+	return name
+}
+
+// splitWords splits s at every character that is neither a letter nor a digit.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	})
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && unicode.IsDigit(rune(s[0]))
+}
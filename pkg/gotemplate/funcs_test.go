@@ -0,0 +1,92 @@
+package gotemplate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuncMap(t *testing.T) {
+	funcMap := FuncMap()
+
+	for _, name := range []string{"goPackageName", "goExportedIdent", "protoPackage", "envVarName", "dockerImageName", "k8sName"} {
+		assert.Contains(t, funcMap, name)
+	}
+
+	// sprig's functions are still available
+	assert.Contains(t, funcMap, "replace")
+}
+
+func Test_nameFuncs(t *testing.T) {
+	tests := []struct {
+		input           string
+		goPackageName   string
+		goExportedIdent string
+		protoPackage    string
+		envVarName      string
+		dockerImageName string
+		k8sName         string
+	}{
+		{
+			input:         "myapp",
+			goPackageName: "myapp", goExportedIdent: "Myapp", protoPackage: "myapp",
+			envVarName: "MYAPP", dockerImageName: "myapp", k8sName: "myapp",
+		},
+		{
+			input:         "my-app",
+			goPackageName: "myapp", goExportedIdent: "MyApp", protoPackage: "my_app",
+			envVarName: "MY_APP", dockerImageName: "my-app", k8sName: "my-app",
+		},
+		{
+			input:         "My App v2",
+			goPackageName: "myappv2", goExportedIdent: "MyAppV2", protoPackage: "my_app_v2",
+			envVarName: "MY_APP_V2", dockerImageName: "my-app-v2", k8sName: "my-app-v2",
+		},
+		{
+			input:         "myApp",
+			goPackageName: "myapp", goExportedIdent: "MyApp", protoPackage: "myapp",
+			envVarName: "MYAPP", dockerImageName: "myapp", k8sName: "myapp",
+		},
+		{
+			input:         "1password-cli",
+			goPackageName: "pkg1passwordcli", goExportedIdent: "X1passwordCli", protoPackage: "pkg1password_cli",
+			envVarName: "_1PASSWORD_CLI", dockerImageName: "1password-cli", k8sName: "1password-cli",
+		},
+		{
+			input:         "--my__app..",
+			goPackageName: "myapp", goExportedIdent: "MyApp", protoPackage: "my_app",
+			envVarName: "MY_APP", dockerImageName: "my_app", k8sName: "my-app",
+		},
+		{
+			input:         "type",
+			goPackageName: "pkgtype", goExportedIdent: "Type", protoPackage: "type",
+			envVarName: "TYPE", dockerImageName: "type", k8sName: "type",
+		},
+		{
+			input:         "org/My App",
+			goPackageName: "orgmyapp", goExportedIdent: "OrgMyApp", protoPackage: "org_my_app",
+			envVarName: "ORG_MY_APP", dockerImageName: "org/my-app", k8sName: "org-my-app",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			assert.Equal(t, test.goPackageName, goPackageName(test.input), "goPackageName")
+			assert.Equal(t, test.goExportedIdent, goExportedIdent(test.input), "goExportedIdent")
+			assert.Equal(t, test.protoPackage, protoPackage(test.input), "protoPackage")
+			assert.Equal(t, test.envVarName, envVarName(test.input), "envVarName")
+			assert.Equal(t, test.dockerImageName, dockerImageName(test.input), "dockerImageName")
+			assert.Equal(t, test.k8sName, k8sName(test.input), "k8sName")
+		})
+	}
+}
+
+func Test_protoPackage_keepsParts(t *testing.T) {
+	assert.Equal(t, "acme.my_app.v1", protoPackage("acme.my-app.v1"))
+}
+
+func Test_k8sName_truncates(t *testing.T) {
+	name := k8sName(strings.Repeat("a", 62) + "-bc")
+	assert.Equal(t, strings.Repeat("a", 62), name)
+}
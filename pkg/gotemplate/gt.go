@@ -2,28 +2,60 @@ package gotemplate
 
 import (
 	"bufio"
-	"context"
 	"io"
-	"net/http"
+	"io/fs"
 	"sync"
 	"text/template"
-	"time"
 
-	"github.com/Masterminds/sprig/v3"
-	"github.com/google/go-github/v56/github"
 	"github.com/muesli/termenv"
+	gotemplate "github.com/schwarzit/go-template"
+	ownexec "github.com/schwarzit/go-template/pkg/exec"
 	"github.com/schwarzit/go-template/pkg/repos"
 )
 
 type GT struct {
 	Streams
-	Options         *Options
-	FuncMap         template.FuncMap
+	// Verbose enables streaming the output of executed commands to Out and Err
+	// and reports why defaults fell back to static values.
+	Verbose bool
+	// Strict turns the warnings about unknown keys in values files into errors.
+	Strict bool
+	// Preset is the preset whose values are set before loading the others interactively or from a file.
+	Preset *Preset
+	// PresetDirs are the directories presets are loaded from besides the built-in ones, see LoadPresets.
+	PresetDirs []string
+	// ConfigFiles are the config files whose defaults are applied by ApplyConfig, later ones take precedence.
+	ConfigFiles []string
+	// CmdRunner runs the commands to initialize new projects if set.
+	// By default the commands are executed on the host.
+	CmdRunner ownexec.CmdRunner
+	// Template contains the files of the template new projects are generated from.
+	// By default the template embedded in gt is used.
+	Template fs.FS
+	Options  *Options
+	FuncMap  template.FuncMap
+	// TagLister lists the tags of gt's repository to check for newer releases.
+	// If it is not set the release source configured in ConfigFiles or GitHub is used, see releaseSource.
+	TagLister repos.TagLister
+	// GithubTagLister is used like TagLister if TagLister is not set.
+	//
+	// Deprecated: Use TagLister.
 	GithubTagLister repos.GithubTagLister
 	once            sync.Once
 	output          *termenv.Output
 }
 
+func (gt *GT) template() fs.FS {
+	if gt.Template != nil {
+		return gt.Template
+	}
+
+	// the error can be ignored since gotemplate.Key is a valid path
+	templateFS, _ := fs.Sub(gotemplate.FS, gotemplate.Key)
+
+	return templateFS
+}
+
 func (gt *GT) styler() *termenv.Output {
 	if gt.output != nil {
 		return gt.output
@@ -49,24 +81,10 @@ type Streams struct {
 }
 
 func New() *GT {
-	githubClient := github.NewClient(&http.Client{Timeout: time.Second})
-	githubTagLister := repos.GithubTagListerFunc(func(ctx context.Context, owner, repo string) ([]string, error) {
-		tags, _, err := githubClient.Repositories.ListTags(ctx, owner, repo, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		var tagStrings []string
-		for _, tag := range tags {
-			tagStrings = append(tagStrings, tag.GetName())
-		}
-
-		return tagStrings, nil
-	})
-
 	return &GT{
-		Options:         NewOptions(githubTagLister),
-		GithubTagLister: githubTagLister,
-		FuncMap:         sprig.TxtFuncMap(),
+		Options:     NewOptions(nil),
+		FuncMap:     FuncMap(),
+		PresetDirs:  DefaultPresetDirs(),
+		ConfigFiles: DefaultConfigFiles(),
 	}
 }
@@ -0,0 +1,371 @@
+package gotemplate
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrUnknownHookDependency = errors.New("hook depends on unknown option")
+	ErrHookCycle             = errors.New("hook dependency cycle")
+)
+
+// HookStage is a point in the lifecycle of generating a project at which hooks are run.
+type HookStage int
+
+const (
+	// HookPreRender hooks run before any file is rendered.
+	// They can add values to the OptionValues (see HookContext.SetValue) that the template can use.
+	HookPreRender HookStage = iota
+	// HookRenderFile hooks transform the content of every rendered file before it is formatted and written.
+	// Binary files and files that are copied as is (see RenderRaw) are not passed to them.
+	HookRenderFile
+	// HookPostRender hooks run after all files have been rendered into the (staging) directory.
+	HookPostRender
+	// HookPostInit hooks run after the project has been moved into place and git and Go modules were initialized.
+	HookPostInit
+)
+
+func (s HookStage) String() string {
+	switch s {
+	case HookPreRender:
+		return "pre-render"
+	case HookRenderFile:
+		return "render-file"
+	case HookPostRender:
+		return "post-render"
+	case HookPostInit:
+		return "post-init"
+	default:
+		return fmt.Sprintf("HookStage(%d)", int(s))
+	}
+}
+
+// HookFunc is run at a HookStage for an option.
+type HookFunc func(hc *HookContext) error
+
+// FileHookFunc transforms the content of the generated file at filePath (relative to the project root).
+type FileHookFunc func(hc *HookContext, filePath string, content []byte) ([]byte, error)
+
+// Hook is run for an option at a specific stage of generating a project.
+type Hook struct {
+	Stage HookStage
+	// After contains options whose hooks of the same stage have to run before this one.
+	// Base options are referenced by their name, extension options by "<category>.<name>".
+	// Without any dependencies hooks are run in the order of the options.
+	After []string
+	// Run is called for all stages except HookRenderFile.
+	Run HookFunc
+	// TransformFile is called for HookRenderFile.
+	TransformFile FileHookFunc
+}
+
+// HookContext contains everything a hook needs to know about the option it belongs to and the project.
+type HookContext struct {
+	Context context.Context
+	// Category is the name of the extension category of the option, empty for base options.
+	Category string
+	Option   *Option
+	// Value is the value of the option.
+	Value        interface{}
+	OptionValues *OptionValues
+	// Dir is the directory the project is generated in.
+	// It is empty for HookPreRender since nothing has been written yet.
+	Dir string
+}
+
+// SetValue adds a value to the OptionValues next to the hook's option,
+// so that it can be used in the template like the option's value.
+func (hc *HookContext) SetValue(name string, value interface{}) {
+	if hc.Category == "" {
+		hc.OptionValues.Base[name] = value
+		return
+	}
+
+	if hc.OptionValues.Extensions[hc.Category] == nil {
+		hc.OptionValues.Extensions[hc.Category] = OptionNameToValue{}
+	}
+
+	hc.OptionValues.Extensions[hc.Category][name] = value
+}
+
+// optionHook is a hook together with the option it belongs to.
+type optionHook struct {
+	// key references the option in Hook.After.
+	key      string
+	category string
+	option   *Option
+	hook     Hook
+}
+
+func (h optionHook) context(ctx context.Context, optionValues *OptionValues, dir string) (*HookContext, bool) {
+	values := optionValues.Base
+	if h.category != "" {
+		values = optionValues.Extensions[h.category]
+	}
+
+	value, ok := values[h.option.Name()]
+	if !ok {
+		return nil, false
+	}
+
+	return &HookContext{
+		Context:      ctx,
+		Category:     h.category,
+		Option:       h.option,
+		Value:        value,
+		OptionValues: optionValues,
+		Dir:          dir,
+	}, true
+}
+
+// hooks returns the hooks of all options for stage in the order they need to be run.
+func (o *Options) hooks(stage HookStage) ([]optionHook, error) {
+	var all []optionHook
+	keys := map[string]bool{}
+
+	add := func(key, category string, option *Option) {
+		keys[key] = true
+
+		for _, hook := range option.allHooks() {
+			if hook.Stage == stage {
+				all = append(all, optionHook{key: key, category: category, option: option, hook: hook})
+			}
+		}
+	}
+
+	for i := range o.Base {
+		add(o.Base[i].Name(), "", &o.Base[i])
+	}
+
+	for _, category := range o.Extensions {
+		for i := range category.Options {
+			add(optionKey(category.Name, category.Options[i].Name()), category.Name, &category.Options[i])
+		}
+	}
+
+	for _, h := range all {
+		if (stage == HookRenderFile && h.hook.TransformFile == nil) || (stage != HookRenderFile && h.hook.Run == nil) {
+			return nil, errors.Wrapf(ErrMalformedInput, "%s hook of %s has no function to run", stage, h.key)
+		}
+
+		for _, dependency := range h.hook.After {
+			if !keys[dependency] {
+				return nil, errors.Wrapf(ErrUnknownHookDependency, "%s hook of %s runs after %q", stage, h.key, dependency)
+			}
+		}
+	}
+
+	return orderHooks(all, stage)
+}
+
+// orderHooks sorts hooks so that every hook runs after the hooks of the options it depends on.
+// Apart from that the order is kept.
+func orderHooks(hooks []optionHook, stage HookStage) ([]optionHook, error) {
+	pending := map[string]int{}
+	for _, h := range hooks {
+		pending[h.key]++
+	}
+
+	ordered := make([]optionHook, 0, len(hooks))
+	done := make([]bool, len(hooks))
+
+	for len(ordered) < len(hooks) {
+		progress := false
+
+		for i, h := range hooks {
+			if done[i] || !dependenciesDone(h, pending) {
+				continue
+			}
+
+			done[i] = true
+			pending[h.key]--
+			ordered = append(ordered, h)
+			progress = true
+
+			// start over to keep the original order as far as possible
+			break
+		}
+
+		if !progress {
+			var blocked []string
+			for i, h := range hooks {
+				if !done[i] {
+					blocked = append(blocked, h.key)
+				}
+			}
+
+			return nil, errors.Wrapf(ErrHookCycle, "%s hooks of %s", stage, strings.Join(blocked, ", "))
+		}
+	}
+
+	return ordered, nil
+}
+
+func dependenciesDone(h optionHook, pending map[string]int) bool {
+	for _, dependency := range h.hook.After {
+		if dependency != h.key && pending[dependency] > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HookResult describes a single hook run by runHooks.
+type HookResult struct {
+	Stage HookStage
+	// Option is the key of the option the hook belongs to, see Hook.After.
+	Option string
+	// Skipped is true if the hook was not run since the option has no value.
+	Skipped bool
+	Err     error
+	// Created and Removed contain the files (relative to the project root) the hook created or removed.
+	Created []string
+	Removed []string
+}
+
+// runHooks runs the hooks of stage for all options, even if some of them fail.
+// Hooks of options without a value are skipped. The errors of all failed hooks are joined.
+func (gt *GT) runHooks(ctx context.Context, stage HookStage, optionValues *OptionValues, dir string) ([]HookResult, error) {
+	hooks, err := gt.Options.hooks(stage)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]HookResult, 0, len(hooks))
+	errs := make([]error, 0, len(hooks))
+
+	for _, h := range hooks {
+		result := HookResult{Stage: stage, Option: h.key}
+
+		hc, ok := h.context(ctx, optionValues, dir)
+		if !ok {
+			result.Skipped = true
+			results = append(results, result)
+
+			continue
+		}
+
+		result.Created, result.Removed, result.Err = runHook(h.hook, hc, dir)
+		if result.Err != nil {
+			result.Err = errors.Wrapf(result.Err, "%s hook of %s", stage, h.key)
+			errs = append(errs, result.Err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, stderrors.Join(errs...)
+}
+
+// runHook runs hook and compares the files in dir before and after.
+func runHook(hook Hook, hc *HookContext, dir string) (created, removed []string, err error) {
+	if dir == "" {
+		return nil, nil, hook.Run(hc)
+	}
+
+	before, err := listFiles(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hookErr := hook.Run(hc)
+
+	after, err := listFiles(dir)
+	if err != nil {
+		return nil, nil, stderrors.Join(hookErr, err)
+	}
+
+	for file := range after {
+		if !before[file] {
+			created = append(created, file)
+		}
+	}
+
+	for file := range before {
+		if !after[file] {
+			removed = append(removed, file)
+		}
+	}
+
+	sort.Strings(created)
+	sort.Strings(removed)
+
+	return created, removed, hookErr
+}
+
+// listFiles returns the paths of all files in dir (relative to dir) except the ones in .git.
+func listFiles(dir string) (map[string]bool, error) {
+	files := map[string]bool{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(relPath)] = true
+
+		return nil
+	})
+
+	return files, err
+}
+
+// printHookResults prints the files created or removed by hooks and in verbose mode which hooks were skipped.
+func (gt *GT) printHookResults(results []HookResult) {
+	for _, result := range results {
+		if result.Skipped {
+			if gt.Verbose {
+				gt.printf("Skipped %s hook of %s since it has no value\n", result.Stage, result.Option)
+			}
+
+			continue
+		}
+
+		for _, file := range result.Created {
+			gt.printf("%s hook of %s created %s\n", result.Stage, result.Option, file)
+		}
+
+		for _, file := range result.Removed {
+			gt.printf("%s hook of %s removed %s\n", result.Stage, result.Option, file)
+		}
+	}
+}
+
+// transformFile runs all HookRenderFile hooks on the content of the file at filePath.
+func transformFile(ctx context.Context, hooks []optionHook, optionValues *OptionValues, dir, filePath string, content []byte) ([]byte, error) {
+	for _, h := range hooks {
+		hc, ok := h.context(ctx, optionValues, dir)
+		if !ok {
+			continue
+		}
+
+		var err error
+		if content, err = h.hook.TransformFile(hc, filePath, content); err != nil {
+			return nil, errors.Wrapf(err, "%s hook of %s", HookRenderFile, h.key)
+		}
+	}
+
+	return content, nil
+}
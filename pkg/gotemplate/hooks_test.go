@@ -0,0 +1,142 @@
+package gotemplate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_hooks(t *testing.T) {
+	noop := func(hc *HookContext) error { return nil }
+
+	newOptions := func(aAfter, bAfter []string) *Options {
+		return &Options{
+			Base: []Option{
+				NewOption("a", "", StaticValue(""), WithHook(Hook{Stage: HookPreRender, After: aAfter, Run: noop})),
+			},
+			Extensions: []Category{
+				{
+					Name: "cat",
+					Options: []Option{
+						NewOption("b", "", StaticValue(""), WithHook(Hook{Stage: HookPreRender, After: bAfter, Run: noop})),
+						NewOption("c", "", StaticValue(""), WithHook(Hook{Stage: HookPostRender, Run: noop})),
+					},
+				},
+			},
+		}
+	}
+
+	keys := func(hooks []optionHook) []string {
+		var keys []string
+		for _, h := range hooks {
+			keys = append(keys, h.key)
+		}
+		return keys
+	}
+
+	t.Run("keeps order of options without dependencies", func(t *testing.T) {
+		hooks, err := newOptions(nil, nil).hooks(HookPreRender)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "cat.b"}, keys(hooks))
+	})
+
+	t.Run("runs hooks after their dependencies", func(t *testing.T) {
+		hooks, err := newOptions([]string{"cat.b"}, nil).hooks(HookPreRender)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cat.b", "a"}, keys(hooks))
+	})
+
+	t.Run("ignores dependencies without hooks in the stage", func(t *testing.T) {
+		hooks, err := newOptions([]string{"cat.c"}, nil).hooks(HookPreRender)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "cat.b"}, keys(hooks))
+	})
+
+	t.Run("error on unknown dependency", func(t *testing.T) {
+		_, err := newOptions([]string{"b"}, nil).hooks(HookPreRender)
+		require.ErrorIs(t, err, ErrUnknownHookDependency)
+	})
+
+	t.Run("error on cycle", func(t *testing.T) {
+		_, err := newOptions([]string{"cat.b"}, []string{"a"}).hooks(HookPreRender)
+		require.ErrorIs(t, err, ErrHookCycle)
+	})
+
+	t.Run("error on hook without function", func(t *testing.T) {
+		options := &Options{Base: []Option{NewOption("a", "", StaticValue(""), WithHook(Hook{Stage: HookRenderFile, Run: noop}))}}
+		_, err := options.hooks(HookRenderFile)
+		require.ErrorIs(t, err, ErrMalformedInput)
+	})
+}
+
+func TestGT_runHooks(t *testing.T) {
+	errFailed := errors.New("failed")
+	var ran []string
+
+	hook := func(key string, fn func(hc *HookContext) error) NewOptionOption {
+		return WithHook(Hook{Stage: HookPostRender, Run: func(hc *HookContext) error {
+			ran = append(ran, key+" in "+hc.Category)
+			return fn(hc)
+		}})
+	}
+
+	out := &bytes.Buffer{}
+	gt := &GT{
+		Streams: Streams{Out: out},
+		Verbose: true,
+		Options: &Options{
+			Base: []Option{
+				NewOption("unset", "", StaticValue(""), hook("unset", func(hc *HookContext) error { return nil })),
+				NewOption("failing", "", StaticValue(""), hook("failing", func(hc *HookContext) error { return errFailed })),
+			},
+			Extensions: []Category{
+				{
+					Name: "cat",
+					Options: []Option{
+						NewOption("files", "", StaticValue(""), hook("files", func(hc *HookContext) error {
+							if err := os.Remove(filepath.Join(hc.Dir, "obsolete.txt")); err != nil {
+								return err
+							}
+							return os.WriteFile(filepath.Join(hc.Dir, "new", "file.txt"), nil, permissionRW)
+						})),
+						NewOption("alsoFailing", "", StaticValue(""), hook("alsoFailing", func(hc *HookContext) error { return errFailed })),
+					},
+				},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "obsolete.txt"), nil, permissionRW))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "new"), permissionRWX))
+
+	values := NewOptionValues()
+	values.Base["failing"] = "value"
+	values.Extensions["cat"] = OptionNameToValue{"files": "value", "alsoFailing": "value"}
+
+	results, err := gt.runHooks(context.Background(), HookPostRender, values, dir)
+
+	// all hooks run although the first one fails
+	require.Equal(t, []string{"failing in ", "files in cat", "alsoFailing in cat"}, ran)
+	require.ErrorIs(t, err, errFailed)
+	require.ErrorContains(t, err, "post-render hook of failing: failed\npost-render hook of cat.alsoFailing: failed")
+
+	require.Equal(t, []HookResult{
+		{Stage: HookPostRender, Option: "unset", Skipped: true},
+		{Stage: HookPostRender, Option: "failing", Err: results[1].Err},
+		{Stage: HookPostRender, Option: "cat.files", Created: []string{"new/file.txt"}, Removed: []string{"obsolete.txt"}},
+		{Stage: HookPostRender, Option: "cat.alsoFailing", Err: results[3].Err},
+	}, results)
+
+	gt.printHookResults(results)
+	assert.Equal(t, `Skipped post-render hook of unset since it has no value
+post-render hook of cat.files created new/file.txt
+post-render hook of cat.files removed obsolete.txt
+`, out.String())
+}
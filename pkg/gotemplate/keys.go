@@ -0,0 +1,170 @@
+package gotemplate
+
+import (
+	stderrors "errors"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// valuesFileKeys are the top level keys of a values file, see OptionValues.
+var valuesFileKeys = []string{"base", "extensions"} //nolint:gochecknoglobals // constant list
+
+var ErrUnknownKey = errors.New("unknown key")
+
+// checkUnknownKeys reports all keys of the values file that don't belong to any option.
+func (gt *GT) checkUnknownKeys(fileBytes []byte, optionValues *OptionValues) error {
+	var topLevel map[string]interface{}
+	if err := yaml.Unmarshal(fileBytes, &topLevel); err != nil {
+		return err
+	}
+
+	var unknown []string
+
+	for _, key := range sortedKeys(topLevel) {
+		if !contains(valuesFileKeys, key) {
+			unknown = append(unknown, describeUnknown(key, valuesFileKeys))
+		}
+	}
+
+	return gt.reportUnknownKeys(append(unknown, gt.Options.unknownOptionKeys(optionValues)...))
+}
+
+// reportUnknownKeys prints a warning for every message about an unknown key.
+// In strict mode they are returned as errors instead.
+func (gt *GT) reportUnknownKeys(unknown []string) error {
+	if gt.Strict {
+		errs := make([]error, 0, len(unknown))
+		for _, msg := range unknown {
+			errs = append(errs, errors.Wrap(ErrUnknownKey, msg))
+		}
+
+		return stderrors.Join(errs...)
+	}
+
+	for _, msg := range unknown {
+		gt.printWarningf("%s: %s, it is ignored", msg, ErrUnknownKey)
+	}
+
+	return nil
+}
+
+// unknownOptionKeys returns a message for every value in optionValues that doesn't belong to any option.
+// If there is a known key that is similar to the unknown one it is suggested.
+func (o *Options) unknownOptionKeys(optionValues *OptionValues) []string {
+	var (
+		unknown    []string
+		categories []string
+		options    = o.keys()
+	)
+
+	for _, category := range o.Extensions {
+		categories = append(categories, category.Name)
+	}
+
+	for _, name := range sortedKeys(optionValues.Base) {
+		if !contains(options, name) {
+			unknown = append(unknown, describeUnknown(name, options))
+		}
+	}
+
+	for _, category := range sortedKeys(optionValues.Extensions) {
+		if !contains(categories, category) {
+			unknown = append(unknown, describeUnknown("extensions."+category, prefixAll("extensions.", categories)))
+			continue
+		}
+
+		for _, name := range sortedKeys(optionValues.Extensions[category]) {
+			if key := optionKey(category, name); !contains(options, key) {
+				unknown = append(unknown, describeUnknown(key, options))
+			}
+		}
+	}
+
+	return unknown
+}
+
+func describeUnknown(key string, known []string) string {
+	if suggestion, ok := suggest(key, known); ok {
+		return fmt.Sprintf("%q (did you mean %q?)", key, suggestion)
+	}
+
+	return fmt.Sprintf("%q", key)
+}
+
+// suggest returns the candidate that is the closest to key, if it is close enough to be a typo.
+func suggest(key string, candidates []string) (string, bool) {
+	best, bestDistance := "", -1
+
+	for _, candidate := range candidates {
+		distance := editDistance(key, candidate)
+		if bestDistance == -1 || distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	// allow roughly one typo for every three characters
+	if bestDistance == -1 || bestDistance > len([]rune(key))/3+1 {
+		return "", false
+	}
+
+	return best, true
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			// deletion, insertion or substitution
+			current[j] = previous[j] + 1
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+			if previous[j-1]+cost < current[j] {
+				current[j] = previous[j-1] + cost
+			}
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func prefixAll(prefix string, values []string) []string {
+	prefixed := make([]string, 0, len(values))
+	for _, value := range values {
+		prefixed = append(prefixed, prefix+value)
+	}
+
+	return prefixed
+}
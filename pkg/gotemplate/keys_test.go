@@ -0,0 +1,29 @@
+package gotemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_editDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("grpc", "grpc"))
+	assert.Equal(t, 1, editDistance("grpcGatway", "grpcGateway"))
+	assert.Equal(t, 2, editDistance("gprc", "grpc"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
+
+func Test_suggest(t *testing.T) {
+	candidates := []string{"projectName", "projectSlug", "grpc.base"}
+
+	suggestion, ok := suggest("projectname", candidates)
+	assert.True(t, ok)
+	assert.Equal(t, "projectName", suggestion)
+
+	_, ok = suggest("license", candidates)
+	assert.False(t, ok)
+
+	_, ok = suggest("a", nil)
+	assert.False(t, ok)
+}
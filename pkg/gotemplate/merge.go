@@ -0,0 +1,232 @@
+package gotemplate
+
+import (
+	"bytes"
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// sideFileSuffix is appended to generated files that conflict with existing ones when using ConflictSideFile.
+const sideFileSuffix = ".gt-new"
+
+// ConflictPolicy defines how generated files are handled that already exist in the target directory.
+type ConflictPolicy string
+
+const (
+	// ConflictFail aborts the generation on the first conflicting file.
+	ConflictFail ConflictPolicy = ""
+	// ConflictSkip keeps the existing file.
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictOverwrite replaces the existing file with the generated one.
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictSideFile keeps the existing file and writes the generated one next to it with a ".gt-new" suffix.
+	ConflictSideFile ConflictPolicy = "side-file"
+)
+
+// ConflictPolicies contains all policies that can be chosen when merging into an existing directory.
+func ConflictPolicies() []ConflictPolicy {
+	return []ConflictPolicy{ConflictSkip, ConflictOverwrite, ConflictSideFile}
+}
+
+func (p ConflictPolicy) Validate() error {
+	for _, policy := range ConflictPolicies() {
+		if p == policy {
+			return nil
+		}
+	}
+
+	return errors.Wrapf(ErrMalformedInput, "unknown conflict policy %q (expected one of %v)", p, ConflictPolicies())
+}
+
+// mergeResult lists the files that existed already in the target directory.
+type mergeResult struct {
+	Skipped     []string
+	Overwritten []string
+	SideFiles   []string
+}
+
+// mergeMove is a generated file or directory that is moved into the target directory.
+type mergeMove struct {
+	source, target string
+	isDir          bool
+}
+
+// mergeInto moves all files of sourceDir into the existing targetDir.
+// Files that exist with the same content already are left untouched,
+// other conflicts are handled according to policy.
+// All conflicts are collected before anything is moved, so targetDir is left untouched if any of them is an error.
+func mergeInto(sourceDir, targetDir string, policy ConflictPolicy) (*mergeResult, error) {
+	result, moves, err := planMerge(sourceDir, targetDir, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, move := range moves {
+		if move.isDir {
+			if err := os.MkdirAll(move.target, permissionRWX); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if err := moveFile(move.source, move.target); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// planMerge walks sourceDir and decides for every file how it is merged into targetDir without changing anything.
+// The errors of all conflicts that can't be resolved with policy are joined.
+func planMerge(sourceDir, targetDir string, policy ConflictPolicy) (*mergeResult, []mergeMove, error) {
+	var (
+		result    = &mergeResult{}
+		moves     []mergeMove
+		conflicts []error
+	)
+
+	err := filepath.WalkDir(sourceDir, func(sourcePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(sourceDir, sourcePath)
+		if err != nil {
+			return err
+		}
+
+		targetPath := filepath.Join(targetDir, relPath)
+		targetInfo, err := os.Stat(targetPath)
+		if os.IsNotExist(err) {
+			moves = append(moves, mergeMove{source: sourcePath, target: targetPath, isDir: d.IsDir()})
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() != targetInfo.IsDir() {
+			conflicts = append(conflicts, errors.Wrapf(ErrAlreadyExists, "%s exists but is of a different type", relPath))
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		same, err := sameContent(sourcePath, targetPath)
+		if err != nil || same {
+			return err
+		}
+
+		switch policy {
+		case ConflictSkip:
+			result.Skipped = append(result.Skipped, relPath)
+		case ConflictOverwrite:
+			result.Overwritten = append(result.Overwritten, relPath)
+			moves = append(moves, mergeMove{source: sourcePath, target: targetPath})
+		case ConflictSideFile:
+			result.SideFiles = append(result.SideFiles, relPath+sideFileSuffix)
+			moves = append(moves, mergeMove{source: sourcePath, target: targetPath + sideFileSuffix})
+		case ConflictFail:
+			conflicts = append(conflicts, errors.Wrapf(ErrAlreadyExists, "file %s", relPath))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(conflicts) > 0 {
+		return nil, nil, stderrors.Join(conflicts...)
+	}
+
+	return result, moves, nil
+}
+
+// moveFile renames source to target.
+// Files are copied and removed instead if they are on different file systems, e.g. if a directory of the target is mounted.
+func moveFile(source, target string) error {
+	err := os.Rename(source, target)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(target, content, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	return os.Remove(source)
+}
+
+func sameContent(a, b string) (bool, error) {
+	aBytes, err := os.ReadFile(a)
+	if err != nil {
+		return false, err
+	}
+
+	bBytes, err := os.ReadFile(b)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(aBytes, bBytes), nil
+}
+
+// isEmptyRepo returns true if dir contains nothing or only a .git folder,
+// which is the case for freshly cloned empty repositories.
+func isEmptyRepo(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range entries {
+		if entry.Name() != ".git" {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (r *mergeResult) String() string {
+	var buffer bytes.Buffer
+
+	for _, file := range r.Skipped {
+		fmt.Fprintf(&buffer, "\n\tkept existing %s", file)
+	}
+
+	for _, file := range r.Overwritten {
+		fmt.Fprintf(&buffer, "\n\toverwrote %s", file)
+	}
+
+	for _, file := range r.SideFiles {
+		fmt.Fprintf(&buffer, "\n\twrote %s", file)
+	}
+
+	return buffer.String()
+}
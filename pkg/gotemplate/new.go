@@ -2,21 +2,25 @@ package gotemplate
 
 import (
 	"bytes"
+	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path"
-	"reflect"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 
-	gotemplate "github.com/schwarzit/go-template"
 	ownexec "github.com/schwarzit/go-template/pkg/exec"
 	"github.com/schwarzit/go-template/pkg/gocli"
 )
@@ -25,6 +29,12 @@ const (
 	minGoVersion  = "1.21"
 	permissionRWX = 0755
 	permissionRW  = 0644
+
+	gitInitTimeout   = 30 * time.Second
+	goModInitTimeout = 5 * time.Minute
+
+	// initLogFile is the file in the project that contains the output of all initialization commands.
+	initLogFile = "gt-init.log"
 )
 
 var (
@@ -35,6 +45,10 @@ var (
 	ErrGoVersionNotSupported = fmt.Errorf("go version is not supported, gt requires at least %s", minGoVersion)
 
 	minGoVersionSemver = semver.MustParse(minGoVersion) //nolint:gochecknoglobals // parsed semver from const minGoVersion
+
+	// templateErrorPattern matches the line, optional column and message of a text/template error
+	// after the "template: <name>:" prefix.
+	templateErrorPattern = regexp.MustCompile(`(?s)^(\d+):(?:\d+:)? (.*)$`)
 )
 
 type ErrTypeMismatch struct {
@@ -46,13 +60,56 @@ func (e *ErrTypeMismatch) Error() string {
 	return fmt.Sprintf("type mismatch, got %s, expected %s", e.Actual, e.Expected)
 }
 
+// RenderError is returned if a file of the template can't be rendered.
+type RenderError struct {
+	// File is the path of the template file relative to the template root.
+	File string
+	// Path is true if rendering the file's path failed instead of its content.
+	Path bool
+	// Line is the line of the content (starting at 1) that failed to render, 0 if unknown.
+	Line int
+	// Snippet is the source of the line that failed to render.
+	Snippet string
+	Err     error
+}
+
+func (e *RenderError) Error() string {
+	if e.Path {
+		return fmt.Sprintf("rendering path of %s: %s", e.File, e.Err.Error())
+	}
+
+	if e.Line == 0 {
+		return fmt.Sprintf("rendering %s: %s", e.File, e.Err.Error())
+	}
+
+	return fmt.Sprintf("rendering %s:%d: %s\n\t%d | %s", e.File, e.Line, e.Err.Error(), e.Line, e.Snippet)
+}
+
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
+
 type NewRepositoryOptions struct {
 	OutputDir    string
 	OptionValues *OptionValues
+	// InPlace renders the project directly into OutputDir instead of a new folder named after the projectSlug.
+	InPlace bool
+	// Merge allows rendering into an existing directory.
+	// Generated files that already exist are handled according to OnConflict.
+	Merge      bool
+	OnConflict ConflictPolicy
+	// FailOnInitErrors returns errors of the initialization commands (e.g. git init) instead of printing them as warnings.
+	FailOnInitErrors bool
 }
 
 // Validate validates all properties of NewRepositoryOptions except the ConfigValues, since those are validated by the Load functions.
 func (opts NewRepositoryOptions) Validate() error {
+	if opts.Merge {
+		if err := opts.OnConflict.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if opts.OutputDir == "" {
 		return nil
 	}
@@ -64,7 +121,8 @@ func (opts NewRepositoryOptions) Validate() error {
 	return nil
 }
 
-// LoadConfigValuesFromFile loads value for the options from a file and validates the inputs
+// LoadConfigValuesFromFile loads value for the options from a file and validates the inputs.
+// Defaults are used for missing options and values of compatible types are coerced, e.g. "1" for an int option.
 func (gt *GT) LoadConfigValuesFromFile(file string) (*OptionValues, error) { //nolint:cyclop // todo refactor
 	fileBytes, err := os.ReadFile(file)
 	if err != nil {
@@ -77,56 +135,93 @@ func (gt *GT) LoadConfigValuesFromFile(file string) (*OptionValues, error) { //n
 		return nil, err
 	}
 
-	for _, option := range gt.Options.Base {
+	if err := gt.checkUnknownKeys(fileBytes, &optionValues); err != nil {
+		return nil, err
+	}
+
+	// values in the file take precedence over the ones of the preset
+	gt.addPresetValues(&optionValues)
+
+	ctx := context.Background()
+	gt.Options.PrefetchDefaults(ctx)
+
+	for i := range gt.Options.Base {
+		option := &gt.Options.Base[i]
+
 		val, ok := optionValues.Base[option.Name()]
-		if !ok || reflect.ValueOf(val).IsZero() {
+		if !ok || val == nil {
+			// like in interactive mode defaults are computed from the values of the options before
+			val = gt.defaultValue(ctx, option.Name(), option, &optionValues)
+		}
+
+		val, err := coerceFileValue(option.Name(), val, option.Default(&optionValues))
+		if err != nil {
+			return nil, err
+		}
+
+		// nil if neither the file nor the default sets a value, zero values like false or 0 are legitimate values
+		if val == nil {
 			return nil, errors.Wrap(ErrParameterNotSet, option.Name())
 		}
 
-		if err := validateFileOption(option, val, optionValues); err != nil {
+		if err := gt.Options.validateFileOption(option.Name(), option, val, optionValues); err != nil {
 			return nil, err
 		}
+
+		if optionValues.Base == nil {
+			optionValues.Base = OptionNameToValue{}
+		}
+
+		optionValues.Base[option.Name()] = val
 	}
 
 	for _, category := range gt.Options.Extensions {
 		if optionValues.Extensions == nil {
 			optionValues.Extensions = map[string]OptionNameToValue{}
 		}
-		for _, option := range category.Options {
+		for i := range category.Options {
+			option := &category.Options[i]
 			if optionValues.Extensions[category.Name] == nil {
 				optionValues.Extensions[category.Name] = OptionNameToValue{}
 			}
+			key := optionKey(category.Name, option.Name())
+
 			val, ok := optionValues.Extensions[category.Name][option.Name()]
-			if !ok {
+			if !ok || val == nil {
 				// set defaults for all unset optionValues, no need to validate
-				optionValues.Extensions[category.Name][option.Name()] = option.Default(&optionValues)
+				optionValues.Extensions[category.Name][option.Name()] = gt.defaultValue(ctx, key, option, &optionValues)
 				continue
 			}
 
-			if err := validateFileOption(option, val, optionValues); err != nil {
+			val, err := coerceFileValue(key, val, option.Default(&optionValues))
+			if err != nil {
 				return nil, err
 			}
+
+			if err := gt.Options.validateFileOption(key, option, val, optionValues); err != nil {
+				return nil, err
+			}
+
+			optionValues.Extensions[category.Name][option.Name()] = val
 		}
 	}
 
 	return &optionValues, nil
 }
 
-func validateFileOption(option Option, value interface{}, optionValues OptionValues) error {
-	valType := reflect.TypeOf(value)
+// validateFileOption validates the value of option read from a file, which has already been coerced to the type of its default.
+func (o *Options) validateFileOption(key string, option *Option, value interface{}, optionValues OptionValues) error {
 	defaultVal := option.Default(&optionValues)
-	defaultType := reflect.TypeOf(defaultVal)
-	if valType != defaultType {
-		return &ErrTypeMismatch{
-			Expected: defaultType.Name(),
-			Actual:   valType.Name(),
-		}
-	}
 
 	if err := option.Validate(value); err != nil {
 		return errors.Wrap(ErrMalformedInput, fmt.Sprintf("%s: %s", option.Name(), err.Error()))
 	}
 
+	// checked before shouldDisplay to explain which other option caused the failure
+	if err := o.validateRelations(key, option, value, &optionValues); err != nil {
+		return err
+	}
+
 	// if it is set to sth else than default with shouldDisplay returning false it means the parameters does not have any effect
 	if value != defaultVal && !option.ShouldDisplay(&optionValues) {
 		return errors.Wrap(ErrParameterSet, option.Name())
@@ -136,11 +231,22 @@ func validateFileOption(option Option, value interface{}, optionValues OptionVal
 }
 
 func (gt *GT) LoadConfigValuesInteractively() (*OptionValues, error) {
+	// defaults that e.g. run commands are computed while the user answers the first questions
+	ctx := context.Background()
+	gt.Options.PrefetchDefaults(ctx)
+
 	gt.printBanner()
 	optionValues := NewOptionValues()
 
 	for i := range gt.Options.Base {
-		val := gt.loadOptionValueInteractively(&gt.Options.Base[i], optionValues)
+		val, ok, err := gt.presetValue(gt.Options.Base[i].Name(), &gt.Options.Base[i], optionValues)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			val = gt.loadOptionValueInteractively(ctx, gt.Options.Base[i].Name(), &gt.Options.Base[i], optionValues)
+		}
 
 		if val == nil {
 			continue
@@ -150,12 +256,41 @@ func (gt *GT) LoadConfigValuesInteractively() (*OptionValues, error) {
 	}
 
 	gt.printProgressf("\nYou now have the option to enable additional extensions (organized in different categories)...\n\n")
-	for _, category := range gt.Options.Extensions {
+	var enabled []string
+
+	for i := range gt.Options.Extensions {
+		category := &gt.Options.Extensions[i]
 		gt.printCategory(category.Name)
 		optionValues.Extensions[category.Name] = OptionNameToValue{}
 
+		// categories the preset sets options of are configured without asking
+		configure := gt.presetSetsCategory(category.Name)
+		if !configure {
+			var err error
+			if configure, err = gt.readCategoryEnabled(category); err != nil {
+				return nil, err
+			}
+		}
+
+		if configure {
+			enabled = append(enabled, category.Name)
+		}
+
 		for i := range category.Options {
-			val := gt.loadOptionValueInteractively(&category.Options[i], optionValues)
+			key := optionKey(category.Name, category.Options[i].Name())
+
+			val, ok, err := gt.presetValue(key, &category.Options[i], optionValues)
+			if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case ok:
+			case !configure:
+				val = gt.defaultValue(ctx, key, &category.Options[i], optionValues)
+			default:
+				val = gt.loadOptionValueInteractively(ctx, key, &category.Options[i], optionValues)
+			}
 
 			if val == nil {
 				continue
@@ -163,171 +298,503 @@ func (gt *GT) LoadConfigValuesInteractively() (*OptionValues, error) {
 
 			optionValues.Extensions[category.Name][category.Options[i].Name()] = val
 		}
+
+		if !configure {
+			gt.printCategoryDefaults(category, optionValues)
+		}
 	}
 
+	gt.printEnabledCategories(enabled)
+
 	return optionValues, nil
 }
 
-func (gt *GT) loadOptionValueInteractively(option *Option, optionValues *OptionValues) interface{} {
+// readCategoryEnabled asks whether the options of category should be configured.
+// Skipping it is the default.
+func (gt *GT) readCategoryEnabled(category *Category) (bool, error) {
+	for {
+		gt.printf("%s [y/N] ", gt.yellowStyler().Styled(category.EnableQuestion()))
+
+		s, err := gt.readStdin()
+		if err != nil {
+			return false, err
+		}
+
+		gt.printf("\n")
+
+		if s == "" {
+			return false, nil
+		}
+
+		if enabled, ok := coerceBool(s); ok {
+			return enabled.(bool), nil
+		}
+
+		gt.printWarningf("%q is no valid answer, please enter y or n", s)
+	}
+}
+
+func (gt *GT) loadOptionValueInteractively(ctx context.Context, key string, option *Option, optionValues *OptionValues) interface{} {
+	// the default is computed before the option is printed to report failures
+	defaultVal := gt.defaultValue(ctx, key, option, optionValues)
 	if !option.ShouldDisplay(optionValues) {
-		return option.Default(optionValues)
+		return defaultVal
 	}
 
-	val, err := gt.readOptionValue(option, optionValues)
+	val, err := gt.readOptionValue(key, option, optionValues)
 	for err != nil {
 		gt.printWarningf(err.Error())
-		val, err = gt.readOptionValue(option, optionValues)
+		val, err = gt.readOptionValue(key, option, optionValues)
 	}
 
 	return val
 }
 
-func (gt *GT) InitNewProject(opts *NewRepositoryOptions) (err error) { //nolint:cyclop // todo refactor
+// defaultValue returns the default of the option referenced by key.
+// If it had to fall back to a static value the reason is printed in verbose mode.
+func (gt *GT) defaultValue(ctx context.Context, key string, option *Option, optionValues *OptionValues) interface{} {
+	value, err := option.DefaultContext(ctx, optionValues)
+	if err != nil && gt.Verbose {
+		gt.printf("Using %v as default of %s: %s\n", value, key, err)
+	}
+
+	return value
+}
+
+// InitNewProject renders the template into a new project folder and initializes it.
+// The project is rendered into a staging directory first (see makeStagingDir),
+// which is only moved into place once rendering and all post hooks succeeded.
+// Once ctx is done generation is aborted and the staging directory is removed.
+func (gt *GT) InitNewProject(ctx context.Context, opts *NewRepositoryOptions) error {
 	gt.printProgressf("Generating repo folder...")
 
-	targetDir := path.Join(opts.OutputDir, opts.OptionValues.Base["projectSlug"].(string))
+	moduleName, ok := opts.OptionValues.Base["moduleName"].(string)
+	if !ok {
+		return errors.Wrap(ErrParameterNotSet, "moduleName")
+	}
+
+	targetDir := opts.targetDir()
 	gt.printProgressf("Writing to %s...", targetDir)
 
-	if _, err := os.Stat(targetDir); !os.IsNotExist(err) {
-		return errors.Wrapf(ErrAlreadyExists, "directory %s", targetDir)
+	targetExists, err := opts.checkTargetDir(targetDir)
+	if err != nil {
+		return err
+	}
+
+	absTargetDir, err := filepath.Abs(targetDir)
+	if err != nil {
+		return err
+	}
+
+	stagingDir, err := makeStagingDir(absTargetDir, targetExists)
+	if err != nil {
+		return err
+	}
+	// after a successful rename or merge the staging directory doesn't exist anymore, so this only cleans up on errors
+	defer os.RemoveAll(stagingDir)
+
+	// pre-render hooks might add values that are used in the template
+	hookResults, err := gt.runHooks(ctx, HookPreRender, opts.OptionValues, "")
+	gt.printHookResults(hookResults)
+	if err != nil {
+		return err
 	}
 
-	defer func() {
+	if err := gt.renderTemplate(ctx, stagingDir, opts.OptionValues); err != nil {
+		return err
+	}
+
+	gt.printProgressf("Running post-render hooks...")
+	hookResults, err = gt.runHooks(ctx, HookPostRender, opts.OptionValues, stagingDir)
+	gt.printHookResults(hookResults)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return errors.Wrap(err, "aborted generation")
+	}
+
+	if targetExists {
+		result, err := mergeInto(stagingDir, targetDir, opts.conflictPolicy())
 		if err != nil {
-			// ignore error to not overwrite original error
-			_ = os.RemoveAll(targetDir)
+			return err
 		}
-	}()
-	err = fs.WalkDir(gotemplate.FS, gotemplate.Key, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
+
+		// skipped files are left in the staging directory, which must be gone before the repo is initialized
+		if err := os.RemoveAll(stagingDir); err != nil {
 			return err
 		}
 
-		pathToWrite, err := gt.executeTemplateString(path, opts.OptionValues)
+		if len(result.Skipped)+len(result.Overwritten)+len(result.SideFiles) > 0 {
+			gt.printWarningf("some generated files already existed:%s", result)
+		}
+	} else if err := moveIntoPlace(stagingDir, targetDir); err != nil {
+		return err
+	}
+
+	gt.printProgressf("Initializing git and Go modules...")
+	if err := gt.initRepo(ctx, targetDir, moduleName); err != nil {
+		if opts.FailOnInitErrors {
+			return errors.Wrap(err, "initializing project")
+		}
+
+		gt.printWarningf(err.Error())
+		gt.printWarningf("one or more initialization steps failed, pls see warnings and %s for more info.", initLogFile)
+	}
+
+	hookResults, err = gt.runHooks(ctx, HookPostInit, opts.OptionValues, targetDir)
+	gt.printHookResults(hookResults)
+
+	return err
+}
+
+// targetDir returns the directory the project is generated in.
+func (opts *NewRepositoryOptions) targetDir() string {
+	if opts.InPlace {
+		return opts.OutputDir
+	}
+
+	return path.Join(opts.OutputDir, opts.OptionValues.Base["projectSlug"].(string))
+}
+
+// checkTargetDir returns whether targetDir exists already and an error if that's not allowed.
+// Existing directories are allowed when merging or if they are empty repos when generating in place.
+func (opts *NewRepositoryOptions) checkTargetDir(targetDir string) (bool, error) {
+	if _, err := os.Stat(targetDir); os.IsNotExist(err) {
+		return false, nil
+	}
+
+	if opts.Merge {
+		return true, nil
+	}
+
+	if opts.InPlace {
+		empty, err := isEmptyRepo(targetDir)
+		if err != nil {
+			return false, err
+		}
+
+		if empty {
+			return true, nil
+		}
+	}
+
+	return false, errors.Wrapf(ErrAlreadyExists, "directory %s (use merge mode to generate into existing directories)", targetDir)
+}
+
+// conflictPolicy returns the policy for files that exist already.
+// Without merge mode conflicts are not allowed.
+func (opts *NewRepositoryOptions) conflictPolicy() ConflictPolicy {
+	if !opts.Merge {
+		return ConflictFail
+	}
+
+	return opts.OnConflict
+}
+
+// renderTemplate renders all files and file names of the template into dir.
+// Which files are rendered and how their content is rendered is defined by the template's rules file.
+// Files that fail to render don't abort rendering, instead all RenderErrors are joined and returned.
+func (gt *GT) renderTemplate(ctx context.Context, dir string, optionValues *OptionValues) error {
+	templateFS := gt.template()
+
+	rules, err := loadTemplateRules(templateFS, ".")
+	if err != nil {
+		return err
+	}
+
+	fileHooks, err := gt.Options.hooks(HookRenderFile)
+	if err != nil {
+		return err
+	}
+
+	var renderErrs []error
+
+	err = fs.WalkDir(templateFS, ".", func(relPath string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		pathToWrite = strings.ReplaceAll(pathToWrite, gotemplate.Key, targetDir)
-		if d.IsDir() {
-			return os.MkdirAll(pathToWrite, permissionRWX)
+		if err := ctx.Err(); err != nil {
+			return errors.Wrap(err, "aborted generation")
 		}
 
-		fileBytes, err := fs.ReadFile(gotemplate.FS, path)
+		if relPath == rulesFile {
+			return nil
+		}
+
+		included, err := gt.isIncluded(rules, relPath, optionValues)
 		if err != nil {
 			return err
 		}
 
-		// skip added Go tests (e.g. internal/log package) since its test braces trigger the text template
-		// and raises an error specifying that the function "Name" cannot be found (or, the name of the first
-		// element of the testcase data structure)
-		var data string
-		if strings.HasSuffix(pathToWrite, "_test.go") {
-			data = string(fileBytes)
-		} else {
-			data, err = gt.executeTemplateString(string(fileBytes), opts.OptionValues)
-			if err != nil {
-				return err
+		if !included {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		renderedPath, err := gt.executeTemplateString(relPath, relPath, optionValues)
+		if err != nil {
+			renderErrs = append(renderErrs, newRenderError(relPath, relPath, true, err))
+
+			// the content of a directory can't be rendered without its path
+			if d.IsDir() {
+				return fs.SkipDir
 			}
+
+			return nil
+		}
+
+		pathToWrite := filepath.Join(dir, filepath.FromSlash(renderedPath))
+		if d.IsDir() {
+			return os.MkdirAll(pathToWrite, permissionRWX)
+		}
+
+		data, err := gt.renderFile(templateFS, rules, relPath, optionValues, func(content []byte) ([]byte, error) {
+			return transformFile(ctx, fileHooks, optionValues, dir, renderedPath, content)
+		})
+
+		var renderErr *RenderError
+		if errors.As(err, &renderErr) {
+			renderErrs = append(renderErrs, err)
+			return nil
 		}
 
-		filePermissions := fs.FileMode(permissionRW)
-		// files that contain a shebang should be executable
-		if strings.HasPrefix(strings.TrimSpace(data), "#!") {
-			filePermissions = permissionRWX
+		if err != nil {
+			return err
 		}
 
-		return os.WriteFile(pathToWrite, []byte(data), filePermissions)
+		return writeFile(templateFS, rules, relPath, pathToWrite, data)
 	})
 	if err != nil {
 		return err
 	}
 
-	gt.printProgressf("Removing obsolete files of unused integrations...")
-	if err := postHook(gt.Options, opts.OptionValues, targetDir); err != nil {
+	return stderrors.Join(renderErrs...)
+}
+
+// renderFile renders the content of the template file at relPath.
+// The rendered content is passed through transform before it is formatted.
+// Binary files and files that are copied as is are neither transformed nor formatted.
+func (gt *GT) renderFile(templateFS fs.FS, rules *templateRules, relPath string, optionValues *OptionValues, transform func([]byte) ([]byte, error)) ([]byte, error) {
+	fileBytes, err := fs.ReadFile(templateFS, relPath)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := rules.renderRule(relPath)
+	// binary files would be corrupted by text/template
+	if rule.Mode != RenderTemplate || rules.isBinary(relPath, fileBytes) {
+		return fileBytes, nil
+	}
+
+	leftDelim, rightDelim := rule.delims()
+	rendered, err := gt.executeTemplateStringWithDelims(relPath, string(fileBytes), optionValues, leftDelim, rightDelim)
+	if err != nil {
+		return nil, newRenderError(relPath, string(fileBytes), false, err)
+	}
+
+	transformed, err := transform([]byte(rendered))
+	if err != nil {
+		return nil, err
+	}
+
+	// conditional blocks and hooks might leave unformatted code or even break the syntax of a file
+	formatted, err := formatRendered(relPath, transformed)
+	if err != nil {
+		return nil, &RenderError{File: relPath, Err: err}
+	}
+
+	return formatted, nil
+}
+
+// writeFile writes the rendered data of the template file at relPath to pathToWrite.
+func writeFile(templateFS fs.FS, rules *templateRules, relPath, pathToWrite string, data []byte) error {
+	info, err := fs.Stat(templateFS, relPath)
+	if err != nil {
+		return err
+	}
+
+	perm, explicit := rules.fileMode(relPath, info.Mode(), data)
+	if err := os.WriteFile(pathToWrite, data, perm); err != nil {
 		return err
 	}
 
-	gt.printProgressf("Initializing git and Go modules...")
-	gt.initRepo(targetDir, opts.OptionValues.Base["moduleName"].(string))
+	// explicit modes are applied exactly instead of being restricted by the umask
+	if explicit {
+		return os.Chmod(pathToWrite, perm)
+	}
 
 	return nil
 }
 
-func (gt *GT) initRepo(targetDir, moduleName string) {
-	commandGroups := []ownexec.CommandGroup{
+// newRenderError creates a RenderError for err returned by text/template when rendering source of file.
+// The position is taken from the error message since text/template doesn't expose it.
+func newRenderError(file, source string, isPath bool, err error) *RenderError {
+	renderErr := &RenderError{File: file, Path: isPath, Err: err}
+
+	matches := templateErrorPattern.FindStringSubmatch(strings.TrimPrefix(err.Error(), "template: "+file+":"))
+	if matches == nil {
+		return renderErr
+	}
+
+	renderErr.Err = errors.New(matches[2])
+	if isPath {
+		return renderErr
+	}
+
+	renderErr.Line, _ = strconv.Atoi(matches[1])
+	if lines := strings.Split(source, "\n"); renderErr.Line > 0 && renderErr.Line <= len(lines) {
+		renderErr.Snippet = strings.TrimSpace(lines[renderErr.Line-1])
+	}
+
+	return renderErr
+}
+
+// makeStagingDir creates the directory the project is rendered into before it is moved to targetDir.
+// New target directories are renamed as a whole, so the staging directory is created next to them.
+// Existing ones are merged into, so it is created inside of them instead since their parent might not be writable
+// and files can't be renamed across file systems.
+func makeStagingDir(targetDir string, targetExists bool) (string, error) {
+	parent, pattern := filepath.Dir(targetDir), fmt.Sprintf(".%s.gt-staging-*", filepath.Base(targetDir))
+	if targetExists {
+		parent, pattern = targetDir, ".gt-staging-*"
+	}
+
+	stagingDir, err := os.MkdirTemp(parent, pattern)
+	if err != nil {
+		return "", errors.Wrap(err, "creating staging directory")
+	}
+
+	// MkdirTemp creates the directory only accessible by the current user
+	if err := os.Chmod(stagingDir, permissionRWX); err != nil {
+		_ = os.RemoveAll(stagingDir)
+		return "", err
+	}
+
+	return stagingDir, nil
+}
+
+// moveIntoPlace renames stagingDir to targetDir.
+// Since renaming would replace an empty directory on some platforms it is checked
+// that targetDir has not been created in the meantime.
+func moveIntoPlace(stagingDir, targetDir string) error {
+	if _, err := os.Stat(targetDir); !os.IsNotExist(err) {
+		return errors.Wrapf(ErrAlreadyExists, "directory %s appeared while generating the project", targetDir)
+	}
+
+	if err := os.Rename(stagingDir, targetDir); err != nil {
+		if _, statErr := os.Stat(targetDir); statErr == nil {
+			return errors.Wrapf(ErrAlreadyExists, "directory %s appeared while generating the project", targetDir)
+		}
+
+		return errors.Wrapf(err, "moving project to %s", targetDir)
+	}
+
+	return nil
+}
+
+func (gt *GT) initRepo(ctx context.Context, targetDir, moduleName string) error {
+	var logWriter io.Writer
+
+	logFile, err := os.Create(path.Join(targetDir, initLogFile))
+	if err != nil {
+		gt.printWarningf("unable to create %s, command output is not logged: %s", initLogFile, err.Error())
+	} else {
+		defer logFile.Close()
+		logWriter = logFile
+	}
+
+	runner := gt.cmdRunner(logWriter)
+
+	goCommands := []*exec.Cmd{
+		exec.Command("go", "mod", "init", moduleName),
+		exec.Command("go", "mod", "tidy"),
+	}
+	// existing modules are kept when generating into an existing directory
+	if exists(path.Join(targetDir, "go.mod")) {
+		goCommands = goCommands[1:]
+	}
+
+	commandGroups := []*ownexec.CommandGroup{
 		{
-			Commands: []*exec.Cmd{
-				exec.Command("git", "init"),
+			Name: "go modules",
+			PreRun: func() error {
+				return checkGoVersion(runner)
 			},
+			Commands:  goCommands,
 			TargetDir: targetDir,
+			Timeout:   goModInitTimeout,
 		},
-		{
-			PreRun: checkGoVersion,
+	}
+
+	// existing repositories (e.g. a freshly cloned one) must not be initialized again
+	if !exists(path.Join(targetDir, ".git")) {
+		commandGroups = append(commandGroups, &ownexec.CommandGroup{
+			Name: "git",
 			Commands: []*exec.Cmd{
-				exec.Command("go", "mod", "init", moduleName),
-				exec.Command("go", "mod", "tidy"),
+				exec.Command("git", "init"),
 			},
 			TargetDir: targetDir,
-		},
+			Timeout:   gitInitTimeout,
+		})
 	}
 
-	failedCGs := 0
-	for _, cg := range commandGroups {
-		if err := cg.Run(); err != nil {
-			gt.printWarningf(err.Error())
-			failedCGs++
-		}
+	return ownexec.RunGroups(ctx, runner, commandGroups...)
+}
+
+// cmdRunner returns gt.CmdRunner if set.
+// Otherwise it returns a runner that tees the output of all commands to logFile (if not nil)
+// and additionally streams it to gt.Out and gt.Err in verbose mode.
+func (gt *GT) cmdRunner(logFile io.Writer) ownexec.CmdRunner {
+	if gt.CmdRunner != nil {
+		return gt.CmdRunner
 	}
 
-	if failedCGs > 0 {
-		gt.printWarningf("one or more initialization steps failed, pls see warnings for more info.")
+	var stdout, stderr []io.Writer
+
+	if logFile != nil {
+		stdout = append(stdout, logFile)
+		stderr = append(stderr, logFile)
 	}
-}
 
-func checkGoVersion() error {
-	goSemver, err := gocli.Semver()
-	if err != nil {
-		return err
+	if gt.Verbose {
+		stdout = append(stdout, gt.Out)
+		stderr = append(stderr, gt.Err)
 	}
 
-	if goSemver.LessThan(minGoVersionSemver) {
-		return errors.Wrap(ErrGoVersionNotSupported, goSemver.String())
+	if len(stdout) == 0 {
+		return ownexec.NewExecCmdRunner()
 	}
 
-	return nil
+	return ownexec.NewStreamingCmdRunner(io.MultiWriter(stdout...), io.MultiWriter(stderr...))
 }
 
-func postHook(options *Options, optionValues *OptionValues, targetDir string) error {
-	for _, option := range options.Base {
-		optionValue, ok := optionValues.Base[option.Name()]
-		if !ok {
-			return nil
-		}
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
 
-		if err := option.PostHook(optionValue, optionValues, targetDir); err != nil {
-			return err
-		}
+func checkGoVersion(runner ownexec.CmdRunner) error {
+	goSemver, err := gocli.SemverWith(runner)
+	if err != nil {
+		return err
 	}
 
-	for _, category := range options.Extensions {
-		for _, option := range category.Options {
-			optionValue, ok := optionValues.Extensions[category.Name][option.Name()]
-			if !ok {
-				return nil
-			}
-
-			if err := option.PostHook(optionValue, optionValues, targetDir); err != nil {
-				return err
-			}
-		}
+	if goSemver.LessThan(minGoVersionSemver) {
+		return errors.Wrap(ErrGoVersionNotSupported, goSemver.String())
 	}
 
 	return nil
 }
 
 // readOptionValue reads a value for an option from the cli.
-func (gt *GT) readOptionValue(opt *Option, optionValues *OptionValues) (interface{}, error) {
+// key references the option in the messages of failed relations, see Relation.Option.
+func (gt *GT) readOptionValue(key string, opt *Option, optionValues *OptionValues) (interface{}, error) {
 	gt.printOption(opt, optionValues)
 	defer fmt.Fprintln(gt.Out)
 
@@ -336,6 +803,11 @@ func (gt *GT) readOptionValue(opt *Option, optionValues *OptionValues) (interfac
 		return nil, err
 	}
 
+	if help := opt.Help(optionValues); s == "?" && help != "" {
+		gt.printf("\n%s\n", help)
+		return gt.readOptionValue(key, opt, optionValues)
+	}
+
 	defaultVal := opt.Default(optionValues)
 
 	var returnVal interface{}
@@ -364,10 +836,15 @@ func (gt *GT) readOptionValue(opt *Option, optionValues *OptionValues) (interfac
 		}
 	}
 
-	if err := opt.Validate(returnVal); err != nil {
+	err = opt.Validate(returnVal)
+	if err == nil {
+		err = gt.Options.validateRelations(key, opt, returnVal, optionValues)
+	}
+
+	if err != nil {
 		gt.printf("\n")
 		gt.printWarningf("Validation failed: %s", err.Error())
-		return gt.readOptionValue(opt, optionValues)
+		return gt.readOptionValue(key, opt, optionValues)
 	}
 
 	return returnVal, nil
@@ -382,8 +859,15 @@ func (gt *GT) readStdin() (string, error) {
 }
 
 // executeTemplateString executes the template in input str with the default p.FuncMap and valueMap as data.
-func (gt *GT) executeTemplateString(str string, optionValues *OptionValues) (string, error) {
-	tmpl, err := template.New("").Funcs(gt.FuncMap).Parse(str)
+// The template is named after name, which shows up in errors.
+func (gt *GT) executeTemplateString(name, str string, optionValues *OptionValues) (string, error) {
+	return gt.executeTemplateStringWithDelims(name, str, optionValues, "", "")
+}
+
+// executeTemplateStringWithDelims is like executeTemplateString but uses the passed action delimiters.
+// Empty delimiters default to "{{" and "}}".
+func (gt *GT) executeTemplateStringWithDelims(name, str string, optionValues *OptionValues, leftDelim, rightDelim string) (string, error) {
+	tmpl, err := template.New(name).Delims(leftDelim, rightDelim).Funcs(gt.FuncMap).Parse(str)
 	if err != nil {
 		return "", err
 	}
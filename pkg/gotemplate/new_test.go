@@ -3,23 +3,32 @@ package gotemplate_test
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"flag"
 	"fmt"
 	"io/fs"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
+	ownexec "github.com/schwarzit/go-template/pkg/exec"
 	"github.com/schwarzit/go-template/pkg/gotemplate"
 )
 
 var (
 	errFoundLeftoverTemplateVar = errors.New("Found a leftover template variable in")
+
+	updateTranscripts = flag.Bool("update", false, "record command transcripts in testdata instead of replaying them")
 )
 
 const (
@@ -42,6 +51,15 @@ func TestNewRepositoryOptions_Validate(t *testing.T) {
 		require.NoError(t, opts.Validate())
 	})
 
+	t.Run("unknown conflict policy in merge mode", func(t *testing.T) {
+		opts := gotemplate.NewRepositoryOptions{
+			Merge:      true,
+			OnConflict: "unknown",
+		}
+
+		require.ErrorIs(t, opts.Validate(), gotemplate.ErrMalformedInput)
+	})
+
 	t.Run("OutputDir set to valid dir", func(t *testing.T) {
 		opts := gotemplate.NewRepositoryOptions{
 			OutputDir: t.TempDir(),
@@ -100,12 +118,25 @@ extensions:
 		)
 	})
 
-	t.Run("validates that base parameters are not empty", func(t *testing.T) {
-		_, err := loadValueFromTestFile(t, &gt, fmt.Sprintf(`---
+	t.Run("keeps explicit zero values instead of the defaults", func(t *testing.T) {
+		zeroGT := gotemplate.GT{
+			Options: &gotemplate.Options{
+				Base: []gotemplate.Option{
+					gotemplate.NewOption(optionName, "description", gotemplate.StaticValue("theDefault")),
+					gotemplate.NewOption("count", "description", gotemplate.StaticValue(3)),
+					gotemplate.NewOption("enabled", "description", gotemplate.StaticValue(true)),
+				},
+			},
+		}
+
+		optionValues, err := loadValueFromTestFile(t, &zeroGT, fmt.Sprintf(`---
 base:
-    %s: ""`, optionName))
+    %s: ""
+    count: 0
+    enabled: false`, optionName))
 
-		require.ErrorIs(t, err, gotemplate.ErrParameterNotSet)
+		require.NoError(t, err)
+		require.Equal(t, gotemplate.OptionNameToValue{optionName: "", "count": 0, "enabled": false}, optionValues.Base)
 	})
 
 	t.Run("validates validator if set", func(t *testing.T) {
@@ -191,6 +222,77 @@ base:
 		}, optionValues)
 	})
 
+	t.Run("computes defaults for missing base options in order", func(t *testing.T) {
+		gt.Options = &gotemplate.Options{
+			Base: []gotemplate.Option{
+				gotemplate.NewOption("projectName", "description", gotemplate.StaticValue("Awesome Project")),
+				gotemplate.NewOption("projectSlug", "description", gotemplate.DynamicValue(func(vals *gotemplate.OptionValues) interface{} {
+					return strings.ReplaceAll(strings.ToLower(vals.Base["projectName"].(string)), " ", "-")
+				})),
+				gotemplate.NewOption("appName", "description", gotemplate.DynamicValue(func(vals *gotemplate.OptionValues) interface{} {
+					return vals.Base["projectSlug"].(string)
+				}), gotemplate.WithValidator(gotemplate.RegexValidator(`^[a-z-]+$`, "only lowercase letters and dashes"))),
+			},
+		}
+
+		optionValues, err := loadValueFromTestFile(t, &gt, `---
+base:
+    projectName: Some Project`)
+		require.NoError(t, err)
+		require.Equal(t, gotemplate.OptionNameToValue{
+			"projectName": "Some Project",
+			"projectSlug": "some-project",
+			"appName":     "some-project",
+		}, optionValues.Base)
+
+		// computed defaults are validated as well
+		_, err = loadValueFromTestFile(t, &gt, `---
+base:
+    projectName: Project 2`)
+		require.ErrorIs(t, err, gotemplate.ErrMalformedInput)
+	})
+
+	t.Run("coerces compatible types", func(t *testing.T) {
+		gt.Options = &gotemplate.Options{
+			Base: []gotemplate.Option{
+				gotemplate.NewOption("string", "description", gotemplate.StaticValue("string")),
+			},
+			Extensions: []gotemplate.Category{
+				{
+					Name: "test",
+					Options: []gotemplate.Option{
+						gotemplate.NewOption("int", "description", gotemplate.StaticValue(0)),
+						gotemplate.NewOption("bool", "description", gotemplate.StaticValue(false)),
+						gotemplate.NewOption("otherBool", "description", gotemplate.StaticValue(true)),
+					},
+				},
+			},
+		}
+
+		optionValues, err := loadValueFromTestFile(t, &gt, `---
+base:
+    string: 2024
+extensions:
+    test:
+        int: "1"
+        bool: "yes"
+        otherBool: no`)
+		require.NoError(t, err)
+		require.Equal(t, &gotemplate.OptionValues{
+			Base:       gotemplate.OptionNameToValue{"string": "2024"},
+			Extensions: map[string]gotemplate.OptionNameToValue{"test": {"int": 1, "bool": true, "otherBool": false}},
+		}, optionValues)
+
+		_, err = loadValueFromTestFile(t, &gt, `---
+extensions:
+    test:
+        int: "one"`)
+
+		var errTypeMismatch *gotemplate.ErrTypeMismatch
+		require.ErrorAs(t, err, &errTypeMismatch)
+		require.EqualError(t, err, "test.int: type mismatch, got string, expected int")
+	})
+
 	t.Run("error on type mismatch", func(t *testing.T) {
 		gt.Options.Base[0] = gotemplate.NewOption(
 			optionName,
@@ -206,6 +308,20 @@ base:
 		require.ErrorAs(t, err, &errTypeMismatch)
 	})
 
+	t.Run("error instead of panic if default is nil", func(t *testing.T) {
+		gt.Options.Base[0] = gotemplate.NewOption(
+			optionName,
+			"description",
+			gotemplate.DynamicValue(func(vals *gotemplate.OptionValues) interface{} { return nil }),
+		)
+
+		_, err := loadValueFromTestFile(t, &gt, "base: {}")
+		require.ErrorIs(t, err, gotemplate.ErrParameterNotSet)
+
+		_, err = loadValueFromTestFile(t, &gt, fmt.Sprintf("base:\n    %s: null", optionName))
+		require.ErrorIs(t, err, gotemplate.ErrParameterNotSet)
+	})
+
 	t.Run("error if option is set but shouldDisplay returns false", func(t *testing.T) {
 		gt.Options = &gotemplate.Options{
 			Extensions: []gotemplate.Category{
@@ -254,6 +370,83 @@ extensions:
         option: true`)
 		require.NoError(t, err)
 	})
+
+	t.Run("warns about unknown keys or fails in strict mode", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		gt.Streams = gotemplate.Streams{Out: out, Err: out}
+		gt.Options = &gotemplate.Options{
+			Base: []gotemplate.Option{
+				gotemplate.NewOption("projectName", "description", gotemplate.StaticValue("name")),
+			},
+			Extensions: []gotemplate.Category{
+				{
+					Name: "grpc",
+					Options: []gotemplate.Option{
+						gotemplate.NewOption("grpcGateway", "description", gotemplate.StaticValue(false)),
+					},
+				},
+			},
+		}
+
+		values := `---
+base:
+    projectName: name
+    projectNam: name
+extension: {}
+extensions:
+    grpc:
+        grpcGatway: true
+    gprc:
+        grpcGateway: true
+    something:
+        else: true`
+
+		optionValues, err := loadValueFromTestFile(t, &gt, values)
+		require.NoError(t, err)
+		require.Equal(t, false, optionValues.Extensions["grpc"]["grpcGateway"])
+		require.Equal(t, `WARNING: "extension" (did you mean "extensions"?): unknown key, it is ignored
+WARNING: "projectNam" (did you mean "projectName"?): unknown key, it is ignored
+WARNING: "extensions.gprc" (did you mean "extensions.grpc"?): unknown key, it is ignored
+WARNING: "grpc.grpcGatway" (did you mean "grpc.grpcGateway"?): unknown key, it is ignored
+WARNING: "extensions.something": unknown key, it is ignored
+`, out.String())
+
+		gt.Strict = true
+		defer func() { gt.Strict = false }()
+
+		_, err = loadValueFromTestFile(t, &gt, values)
+		require.ErrorIs(t, err, gotemplate.ErrUnknownKey)
+		require.ErrorContains(t, err, `"grpc.grpcGatway" (did you mean "grpc.grpcGateway"?): unknown key`)
+	})
+
+	t.Run("error names the other option if a relation fails", func(t *testing.T) {
+		gt.Options = &gotemplate.Options{
+			Extensions: []gotemplate.Category{
+				{
+					Name: "test",
+					Options: []gotemplate.Option{
+						gotemplate.NewOption("base", "description", gotemplate.StaticValue(false)),
+						gotemplate.NewOption(
+							"option",
+							"description",
+							gotemplate.StaticValue(false),
+							gotemplate.WithRequires(gotemplate.Relation{Option: "test.base"}),
+						),
+					},
+				},
+			},
+		}
+
+		_, err := loadValueFromTestFile(t, &gt, `---
+extensions:
+    test:
+        base: false
+        option: true`)
+
+		var errRelation *gotemplate.ErrRelation
+		require.ErrorAs(t, err, &errRelation)
+		require.EqualError(t, err, "test.option=true requires test.base to be enabled (it is false)")
+	})
 }
 
 func loadValueFromTestFile(t *testing.T, gt *gotemplate.GT, contents string) (*gotemplate.OptionValues, error) {
@@ -279,7 +472,8 @@ func TestGT_LoadConfigValuesInteractively(t *testing.T) {
 		out := &bytes.Buffer{}
 
 		// simulate writing the value to stdin
-		gt.InScanner = bufio.NewScanner(strings.NewReader(fmt.Sprintf("%s\n true\n", optionValue)))
+		// the category is enabled before its option is set
+		gt.InScanner = bufio.NewScanner(strings.NewReader(fmt.Sprintf("%s\ny\n true\n", optionValue)))
 		gt.Out = out
 		gt.Options.Base = []gotemplate.Option{
 			gotemplate.NewOption(
@@ -318,6 +512,77 @@ func TestGT_LoadConfigValuesInteractively(t *testing.T) {
 		require.Contains(t, out.String(), "CATEGORY")
 	})
 
+	t.Run("skipped categories use defaults", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		gt.Streams = gotemplate.Streams{Out: out, Err: out}
+		// skip the first category after an invalid answer and enable the second one
+		gt.InScanner = bufio.NewScanner(strings.NewReader("maybe\n\nyes\n3\n"))
+		gt.Options = &gotemplate.Options{
+			Extensions: []gotemplate.Category{
+				{
+					Name:     "grpc",
+					Question: "Configure gRPC?",
+					Options: []gotemplate.Option{
+						gotemplate.NewOption("base", "description", gotemplate.StaticValue(true)),
+					},
+				},
+				{
+					Name: "ci",
+					Options: []gotemplate.Option{
+						gotemplate.NewOption("provider", "description", gotemplate.StaticValue(1)),
+					},
+				},
+			},
+		}
+
+		optionValues, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Equal(t, map[string]gotemplate.OptionNameToValue{
+			"grpc": {"base": true},
+			"ci":   {"provider": 3},
+		}, optionValues.Extensions)
+		require.Contains(t, out.String(), "Configure gRPC? [y/N]")
+		require.Contains(t, out.String(), `"maybe" is no valid answer`)
+		require.Contains(t, out.String(), "Using the defaults: base=true")
+		require.Contains(t, out.String(), "Customize ci? [y/N]")
+		require.NotContains(t, out.String(), "base: ")
+		require.Contains(t, out.String(), "Customized categories: ci")
+
+		gt.Options = &gotemplate.Options{}
+	})
+
+	t.Run("skipping prints the defaults of shown options", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		gt.Streams = gotemplate.Streams{Out: out, Err: out}
+		gt.InScanner = bufio.NewScanner(strings.NewReader("n\n"))
+		gt.Options = &gotemplate.Options{
+			Extensions: []gotemplate.Category{
+				{
+					Name:     "ci",
+					Question: "Customize the CI pipeline?",
+					Options: []gotemplate.Option{
+						gotemplate.NewOption("provider", "description", gotemplate.StaticValue(1),
+							gotemplate.WithChoices(gotemplate.Choice{Value: 0, Description: "No CI"}, gotemplate.Choice{Value: 1, Description: "Github"}),
+						),
+						gotemplate.NewOption("runner", "description", gotemplate.StaticValue("self-hosted"),
+							gotemplate.WithShouldDisplay(gotemplate.DynamicBoolValue(func(vals *gotemplate.OptionValues) bool {
+								return vals.Extensions["ci"]["provider"] == 0
+							})),
+						),
+					},
+				},
+			},
+		}
+
+		_, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Customize the CI pipeline? [y/N] \nUsing the defaults: provider=Github\n")
+		require.NotContains(t, out.String(), "runner=")
+		require.Contains(t, out.String(), "No categories customized")
+
+		gt.Options = &gotemplate.Options{}
+	})
+
 	t.Run("checks regex if it is set and retry if no match", func(t *testing.T) {
 		// simulate writing the value to stdin
 		out := &bytes.Buffer{}
@@ -434,6 +699,43 @@ func TestGT_LoadConfigValuesInteractively(t *testing.T) {
 		require.NotContains(t, out.String(), dependentOptionName)
 	})
 
+	t.Run("checks relations and retries if they fail", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		gt.Err = out
+		gt.InScanner = bufio.NewScanner(strings.NewReader("2\ntrue\nfalse\n"))
+		gt.Options.Base = []gotemplate.Option{
+			gotemplate.NewOption("provider", "description", gotemplate.StaticValue(1)),
+			gotemplate.NewOption(
+				optionName,
+				"description",
+				gotemplate.StaticValue(false),
+				gotemplate.WithConflictsWith(gotemplate.Relation{Option: "provider", Value: 2}),
+			),
+		}
+
+		optionValues, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Equal(t, gotemplate.OptionNameToValue{"provider": 2, optionName: false}, optionValues.Base)
+		require.Contains(t, out.String(), "WARNING")
+		require.Contains(t, out.String(), optionName+"=true conflicts with provider being 2 (it is 2)")
+	})
+
+	t.Run("shows help on ?", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		gt.Out = out
+		gt.InScanner = bufio.NewScanner(strings.NewReader("?\nvalue\n"))
+		gt.Options.Base = []gotemplate.Option{
+			gotemplate.NewOption(optionName, "description", gotemplate.StaticValue("default"),
+				gotemplate.WithHelp(gotemplate.StringValue("some longer help"))),
+		}
+
+		optionValues, err := gt.LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		require.Equal(t, gotemplate.OptionNameToValue{optionName: "value"}, optionValues.Base)
+		require.Contains(t, out.String(), `Enter "?" for more information.`)
+		require.Contains(t, out.String(), "some longer help")
+	})
+
 	t.Run("parses non string values", func(t *testing.T) {
 		intOptionName := "intOption"
 		gt.InScanner = bufio.NewScanner(strings.NewReader("false\n4\n"))
@@ -488,6 +790,7 @@ func TestGT_InitNewProject(t *testing.T) {
 	// initialize template.FuncMap
 	gt := gotemplate.New()
 	gt.Streams.Out = &bytes.Buffer{}
+	gt.Streams.Err = &bytes.Buffer{}
 
 	testValuesBytes, err := os.ReadFile("./testdata/values.yml")
 	require.NoError(t, err)
@@ -501,7 +804,7 @@ func TestGT_InitNewProject(t *testing.T) {
 		tmpDir := t.TempDir()
 		opts.OutputDir = tmpDir
 
-		err = gt.InitNewProject(opts)
+		err = gt.InitNewProject(context.Background(), opts)
 		require.NoError(t, err)
 
 		_, err = os.Stat(path.Join(getTargetDir(tmpDir, opts), ".git"))
@@ -511,11 +814,67 @@ func TestGT_InitNewProject(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("runs initialization commands", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		opts.OutputDir = tmpDir
+		transcriptFile := "./testdata/init_repo.transcript.yml"
+		targetDir := getTargetDir(tmpDir, opts)
+
+		errOut := &bytes.Buffer{}
+		gt.Err = errOut
+		defer func() {
+			gt.Err = &bytes.Buffer{}
+			gt.CmdRunner = nil
+		}()
+
+		if *updateTranscripts {
+			recorder := ownexec.NewRecordingCmdRunner(ownexec.NewExecCmdRunner(), targetDir)
+			gt.CmdRunner = recorder
+
+			err := gt.InitNewProject(context.Background(), opts)
+			require.NoError(t, err)
+			require.NoError(t, recorder.Transcript().WriteFile(transcriptFile))
+			return
+		}
+
+		transcript, err := ownexec.LoadTranscript(transcriptFile)
+		require.NoError(t, err)
+
+		replayer := ownexec.NewReplayingCmdRunner(transcript, targetDir)
+		gt.CmdRunner = replayer
+
+		err = gt.InitNewProject(context.Background(), opts)
+		require.NoError(t, err)
+		require.NotContains(t, errOut.String(), "WARNING")
+		require.NoError(t, replayer.Done())
+	})
+
+	t.Run("logs command output to file and streams it in verbose mode", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		opts.OutputDir = tmpDir
+
+		out := &bytes.Buffer{}
+		gt.Out = out
+		gt.Verbose = true
+		defer func() {
+			gt.Out = &bytes.Buffer{}
+			gt.Verbose = false
+		}()
+
+		err = gt.InitNewProject(context.Background(), opts)
+		require.NoError(t, err)
+
+		logBytes, err := os.ReadFile(path.Join(getTargetDir(tmpDir, opts), "gt-init.log"))
+		require.NoError(t, err)
+		require.Contains(t, string(logBytes), "[git] ")
+		require.Contains(t, out.String(), "[git] ")
+	})
+
 	t.Run("copies hidden files (e.g. .gitignore)", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		opts.OutputDir = tmpDir
 
-		err = gt.InitNewProject(opts)
+		err = gt.InitNewProject(context.Background(), opts)
 		require.NoError(t, err)
 
 		testItems := []string{".gitignore", "pkg", "internal", ".golangci.yml"}
@@ -525,11 +884,22 @@ func TestGT_InitNewProject(t *testing.T) {
 		}
 	})
 
+	t.Run("does not copy the template's rules file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		opts.OutputDir = tmpDir
+
+		err = gt.InitNewProject(context.Background(), opts)
+		require.NoError(t, err)
+
+		_, err = os.Stat(path.Join(getTargetDir(tmpDir, opts), ".gtrules.yml"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
 	t.Run("all templates should be resolved (in files and fileNames)", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		opts.OutputDir = tmpDir
 
-		err := gt.InitNewProject(opts)
+		err := gt.InitNewProject(context.Background(), opts)
 		require.NoError(t, err)
 
 		err = filepath.WalkDir(getTargetDir(tmpDir, opts), func(path string, d fs.DirEntry, err error) error {
@@ -566,14 +936,14 @@ func TestGT_InitNewProject(t *testing.T) {
 		err := os.MkdirAll(getTargetDir(tmpDir, opts), os.ModePerm)
 		require.NoError(t, err)
 
-		err = gt.InitNewProject(opts)
+		err = gt.InitNewProject(context.Background(), opts)
 		require.Error(t, err)
 	})
 
 	t.Run("removes all files on error", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		// force error with empty values
-		err = gt.InitNewProject(
+		err = gt.InitNewProject(context.Background(),
 			&gotemplate.NewRepositoryOptions{
 				OutputDir: tmpDir,
 				OptionValues: &gotemplate.OptionValues{
@@ -586,6 +956,57 @@ func TestGT_InitNewProject(t *testing.T) {
 
 		_, err := os.Stat(getTargetDir(tmpDir, opts))
 		require.ErrorIs(t, err, os.ErrNotExist)
+
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		require.Empty(t, entries, "staging directory should be removed")
+	})
+
+	t.Run("aborts and cleans up if context is done", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		opts.OutputDir = tmpDir
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := gt.InitNewProject(ctx, opts)
+		require.ErrorIs(t, err, context.Canceled)
+
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("error if target dir appears while generating", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		gt := gotemplate.New()
+		gt.Out = &bytes.Buffer{}
+		gt.Options.Base = append(gt.Options.Base, gotemplate.NewOption(
+			"createTarget",
+			"description",
+			gotemplate.StaticValue(true),
+			gotemplate.WithPosthook(func(value interface{}, optionValues *gotemplate.OptionValues, _ string) error {
+				return os.Mkdir(path.Join(tmpDir, optionValues.Base[targetDirOptionName].(string)), os.ModePerm)
+			}),
+		))
+
+		optionValues := gotemplate.NewOptionValues()
+		for name, value := range opts.OptionValues.Base {
+			optionValues.Base[name] = value
+		}
+		optionValues.Base["createTarget"] = true
+		optionValues.Extensions = opts.OptionValues.Extensions
+
+		err := gt.InitNewProject(context.Background(), &gotemplate.NewRepositoryOptions{
+			OutputDir:    tmpDir,
+			OptionValues: optionValues,
+		})
+		require.ErrorIs(t, err, gotemplate.ErrAlreadyExists)
+
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1, "only the concurrently created directory should be left")
 	})
 
 	t.Run("postHook not executed if value not set", func(t *testing.T) {
@@ -603,7 +1024,7 @@ func TestGT_InitNewProject(t *testing.T) {
 			}),
 		))
 
-		err := gt.InitNewProject(opts)
+		err := gt.InitNewProject(context.Background(), opts)
 		require.NoError(t, err)
 		require.False(t, postHookTriggered, "postHook should not be triggered")
 	})
@@ -624,12 +1045,366 @@ func TestGT_InitNewProject(t *testing.T) {
 			}),
 		))
 
-		err := gt.InitNewProject(opts)
+		err := gt.InitNewProject(context.Background(), opts)
 		require.NoError(t, err)
 		require.True(t, postHookTriggered, "postHook should be triggered")
 	})
 }
 
+func TestGT_InitNewProject_ExistingDir(t *testing.T) {
+	testValuesBytes, err := os.ReadFile("./testdata/values.yml")
+	require.NoError(t, err)
+
+	var optionValues gotemplate.OptionValues
+	err = yaml.Unmarshal(testValuesBytes, &optionValues)
+	require.NoError(t, err)
+
+	newGT := func(executed *[]string) *gotemplate.GT {
+		// the command groups of initRepo run concurrently
+		var mu sync.Mutex
+
+		gt := gotemplate.New()
+		gt.Out = &bytes.Buffer{}
+		gt.Err = &bytes.Buffer{}
+		gt.CmdRunner = ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+			mu.Lock()
+			*executed = append(*executed, strings.Join(cmd.Args, " "))
+			mu.Unlock()
+
+			if cmd.Args[1] == "version" {
+				return "go version go1.21.3 linux/amd64", nil
+			}
+			return "", nil
+		})
+		return gt
+	}
+
+	t.Run("generates in place into empty repo without initializing git again", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		require.NoError(t, os.Mkdir(path.Join(tmpDir, ".git"), os.ModePerm))
+
+		var executed []string
+		err := newGT(&executed).InitNewProject(context.Background(), &gotemplate.NewRepositoryOptions{
+			OutputDir:    tmpDir,
+			OptionValues: &optionValues,
+			InPlace:      true,
+		})
+		require.NoError(t, err)
+
+		_, err = os.Stat(path.Join(tmpDir, "Makefile"))
+		require.NoError(t, err)
+		require.NotContains(t, executed, "git init")
+		require.Contains(t, executed, "go mod init github.com/fake/testing")
+	})
+
+	t.Run("error in place if directory contains files and merge is disabled", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		require.NoError(t, os.WriteFile(path.Join(tmpDir, "README.md"), []byte("existing"), os.ModePerm))
+
+		var executed []string
+		err := newGT(&executed).InitNewProject(context.Background(), &gotemplate.NewRepositoryOptions{
+			OutputDir:    tmpDir,
+			OptionValues: &optionValues,
+			InPlace:      true,
+		})
+		require.ErrorIs(t, err, gotemplate.ErrAlreadyExists)
+		require.Empty(t, executed)
+	})
+
+	t.Run("reports all conflicts without changing the directory", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		targetDir := path.Join(tmpDir, "testing-project")
+		require.NoError(t, os.MkdirAll(targetDir, os.ModePerm))
+		require.NoError(t, os.WriteFile(path.Join(targetDir, "README.md"), []byte("existing"), os.ModePerm))
+		require.NoError(t, os.WriteFile(path.Join(targetDir, "Makefile"), []byte("existing"), os.ModePerm))
+
+		var executed []string
+		err := newGT(&executed).InitNewProject(context.Background(), &gotemplate.NewRepositoryOptions{
+			OutputDir:    tmpDir,
+			OptionValues: &optionValues,
+			Merge:        true,
+		})
+		require.ErrorIs(t, err, gotemplate.ErrAlreadyExists)
+		require.ErrorContains(t, err, "README.md")
+		require.ErrorContains(t, err, "Makefile")
+		require.Empty(t, executed)
+
+		entries, err := os.ReadDir(targetDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 2, "neither generated nor staging files are left behind")
+	})
+
+	t.Run("generates in place if the parent directory is read-only", func(t *testing.T) {
+		if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+			t.Skip("directory permissions are not enforced")
+		}
+
+		tmpDir := t.TempDir()
+		targetDir := path.Join(tmpDir, "testing-project")
+		require.NoError(t, os.MkdirAll(path.Join(targetDir, ".git"), os.ModePerm))
+		require.NoError(t, os.Chmod(tmpDir, 0o555))
+		defer func() {
+			require.NoError(t, os.Chmod(tmpDir, os.ModePerm))
+		}()
+
+		var executed []string
+		err := newGT(&executed).InitNewProject(context.Background(), &gotemplate.NewRepositoryOptions{
+			OutputDir:    targetDir,
+			OptionValues: &optionValues,
+			InPlace:      true,
+		})
+		require.NoError(t, err)
+
+		_, err = os.Stat(path.Join(targetDir, "Makefile"))
+		require.NoError(t, err)
+	})
+
+	tests := []struct {
+		policy         gotemplate.ConflictPolicy
+		expectedReadme string
+		expectSideFile bool
+	}{
+		{policy: gotemplate.ConflictSkip, expectedReadme: "existing"},
+		{policy: gotemplate.ConflictOverwrite, expectedReadme: "# Testing Project"},
+		{policy: gotemplate.ConflictSideFile, expectedReadme: "existing", expectSideFile: true},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("merges into existing directory with conflict policy %s", test.policy), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			targetDir := path.Join(tmpDir, "testing-project")
+			require.NoError(t, os.MkdirAll(targetDir, os.ModePerm))
+			require.NoError(t, os.WriteFile(path.Join(targetDir, "README.md"), []byte("existing"), os.ModePerm))
+			require.NoError(t, os.WriteFile(path.Join(targetDir, "go.mod"), []byte("module github.com/fake/testing"), os.ModePerm))
+
+			var executed []string
+			err := newGT(&executed).InitNewProject(context.Background(), &gotemplate.NewRepositoryOptions{
+				OutputDir:    tmpDir,
+				OptionValues: &optionValues,
+				Merge:        true,
+				OnConflict:   test.policy,
+			})
+			require.NoError(t, err)
+
+			readme, err := os.ReadFile(path.Join(targetDir, "README.md"))
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(string(readme), test.expectedReadme))
+
+			_, err = os.Stat(path.Join(targetDir, "README.md.gt-new"))
+			require.Equal(t, test.expectSideFile, err == nil)
+
+			_, err = os.Stat(path.Join(targetDir, "Makefile"))
+			require.NoError(t, err, "non conflicting files are generated")
+			require.NotContains(t, executed, "go mod init github.com/fake/testing", "existing modules are kept")
+		})
+	}
+}
+
 func getTargetDir(dir string, opts *gotemplate.NewRepositoryOptions) string {
 	return path.Join(dir, opts.OptionValues.Base[targetDirOptionName].(string))
 }
+
+func TestGT_InitNewProject_FileContents(t *testing.T) {
+	gt := gotemplate.New()
+	gt.Out = &bytes.Buffer{}
+	gt.Err = &bytes.Buffer{}
+	gt.CmdRunner = ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+		return "go version go1.21.3 linux/amd64", nil
+	})
+
+	// contains "{{" and a NUL byte, which would fail text/template or corrupt the file
+	pngBytes := []byte("\x89PNG\r\n\x1a\n\x00\x00{{ .Base.projectName")
+	gt.Template = fstest.MapFS{
+		".gtrules.yml": {Data: []byte(`
+binary: [".dat"]
+modes:
+  - glob: scripts/private.sh
+    mode: "0700"
+`)},
+		"README.md":          {Data: []byte("# {{ .Base.projectName }}\n")},
+		"logo.png":           {Data: pngBytes},
+		"fixture.bin":        {Data: []byte("{{ \x00 }}")},
+		"data.dat":           {Data: []byte("{{ .Base.projectName }}")},
+		"scripts/run.sh":     {Data: []byte("#!/bin/sh\necho {{ .Base.appName }}\n")},
+		"scripts/build":      {Data: []byte("go build ./...\n"), Mode: 0o755},
+		"scripts/private.sh": {Data: []byte("#!/bin/sh\n")},
+	}
+
+	values := gotemplate.NewOptionValues()
+	values.Base = gotemplate.OptionNameToValue{"projectName": "Some Project", "projectSlug": "some-project", "appName": "some", "moduleName": "github.com/user/some-project"}
+
+	tmpDir := t.TempDir()
+	err := gt.InitNewProject(context.Background(), &gotemplate.NewRepositoryOptions{OutputDir: tmpDir, OptionValues: values})
+	require.NoError(t, err)
+
+	projectDir := filepath.Join(tmpDir, "some-project")
+	readFile := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(projectDir, name))
+		require.NoError(t, err)
+		return string(content)
+	}
+	fileMode := func(name string) fs.FileMode {
+		info, err := os.Stat(filepath.Join(projectDir, name))
+		require.NoError(t, err)
+		return info.Mode().Perm()
+	}
+
+	t.Run("renders text files", func(t *testing.T) {
+		require.Equal(t, "# Some Project\n", readFile("README.md"))
+		require.Equal(t, "#!/bin/sh\necho some\n", readFile("scripts/run.sh"))
+	})
+
+	t.Run("copies binary files byte for byte", func(t *testing.T) {
+		require.Equal(t, string(pngBytes), readFile("logo.png"))
+		require.Equal(t, "{{ \x00 }}", readFile("fixture.bin"))
+		require.Equal(t, "{{ .Base.projectName }}", readFile("data.dat"))
+	})
+
+	t.Run("applies file modes", func(t *testing.T) {
+		require.Zero(t, fileMode("README.md")&0o111, "README.md should not be executable")
+		require.NotZero(t, fileMode("scripts/run.sh")&0o100, "shebang should make the file executable")
+		require.NotZero(t, fileMode("scripts/build")&0o100, "executable template file should stay executable")
+		require.Equal(t, fs.FileMode(0o700), fileMode("scripts/private.sh"))
+	})
+}
+
+func TestGT_InitNewProject_RenderErrors(t *testing.T) {
+	gt := gotemplate.New()
+	gt.Out = &bytes.Buffer{}
+	gt.Err = &bytes.Buffer{}
+	gt.CmdRunner = ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+		return "go version go1.21.3 linux/amd64", nil
+	})
+	gt.Template = fstest.MapFS{
+		"README.md":               {Data: []byte("# {{ .Base.projectName }}\n\n{{ .Base.projectName | unknownFunc }}\n")},
+		"Makefile":                {Data: []byte("build:\n\t{{ .Base.appName.foo }}\n")},
+		"{{ .Base.appName }/a.go": {Data: []byte("package a\n")},
+		"main.go":                 {Data: []byte("package main\n\nfunc main() {\n")},
+		"valid.txt":               {Data: []byte("{{ .Base.appName }}\n")},
+	}
+
+	values := gotemplate.NewOptionValues()
+	values.Base = gotemplate.OptionNameToValue{"projectName": "Some Project", "projectSlug": "some-project", "appName": "some", "moduleName": "github.com/user/some-project"}
+
+	tmpDir := t.TempDir()
+	err := gt.InitNewProject(context.Background(), &gotemplate.NewRepositoryOptions{OutputDir: tmpDir, OptionValues: values})
+
+	var renderErr *gotemplate.RenderError
+	require.ErrorAs(t, err, &renderErr)
+
+	// all errors are reported at once
+	require.ErrorContains(t, err, "rendering README.md:3: function \"unknownFunc\" not defined\n\t3 | {{ .Base.projectName | unknownFunc }}")
+	require.ErrorContains(t, err, "rendering Makefile:2: executing \"Makefile\" at <.Base.appName.foo>")
+	require.ErrorContains(t, err, "rendering path of {{ .Base.appName }: unexpected \"}\" in operand")
+	require.ErrorContains(t, err, "rendering main.go: main.go:3:15: expected '}'")
+
+	_, err = os.Stat(filepath.Join(tmpDir, "some-project"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestGT_InitNewProject_Hooks(t *testing.T) {
+	var (
+		stages []string
+		// commands of different groups run concurrently
+		commands   int
+		commandsMu sync.Mutex
+	)
+
+	gt := gotemplate.New()
+	gt.Out = &bytes.Buffer{}
+	gt.Err = &bytes.Buffer{}
+	gt.CmdRunner = ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+		commandsMu.Lock()
+		defer commandsMu.Unlock()
+		commands++
+		return "go version go1.21.3 linux/amd64", nil
+	})
+	gt.Template = fstest.MapFS{
+		"README.md": {Data: []byte("# {{ .Extensions.grpc.serviceName }}\n")},
+		"main.go":   {Data: []byte("package main\n")},
+		// binary files are copied as is without running the hooks
+		"logo.png": {Data: []byte("\x89PNG\x00{{")},
+	}
+	gt.Options.Extensions = []gotemplate.Category{
+		{
+			Name: "grpc",
+			Options: []gotemplate.Option{
+				gotemplate.NewOption("base", "gRPC", gotemplate.StaticValue(true),
+					// depends on the hook of the option below
+					gotemplate.WithHook(gotemplate.Hook{
+						Stage: gotemplate.HookPreRender,
+						After: []string{"grpc.name"},
+						Run: func(hc *gotemplate.HookContext) error {
+							stages = append(stages, "pre-render grpc.base")
+							hc.SetValue("serviceName", hc.OptionValues.Extensions["grpc"]["name"].(string)+"Service")
+							return nil
+						},
+					}),
+					gotemplate.WithHook(gotemplate.Hook{
+						Stage: gotemplate.HookRenderFile,
+						TransformFile: func(hc *gotemplate.HookContext, filePath string, content []byte) ([]byte, error) {
+							stages = append(stages, "render-file "+filePath)
+							if filepath.Ext(filePath) == ".go" {
+								// formatted after the hooks ran
+								return append(content, "func   generated( ) {}"...), nil
+							}
+							return append(content, "generated\n"...), nil
+						},
+					}),
+					gotemplate.WithHook(gotemplate.Hook{
+						Stage: gotemplate.HookPostRender,
+						Run: func(hc *gotemplate.HookContext) error {
+							stages = append(stages, "post-render")
+							return os.WriteFile(filepath.Join(hc.Dir, "hook.txt"), nil, os.ModePerm)
+						},
+					}),
+					gotemplate.WithHook(gotemplate.Hook{
+						Stage: gotemplate.HookPostInit,
+						Run: func(hc *gotemplate.HookContext) error {
+							stages = append(stages, fmt.Sprintf("post-init %s after %d commands", filepath.Base(hc.Dir), commands))
+							return nil
+						},
+					}),
+				),
+				gotemplate.NewOption("name", "name", gotemplate.StaticValue("Some"),
+					gotemplate.WithHook(gotemplate.Hook{
+						Stage: gotemplate.HookPreRender,
+						Run: func(hc *gotemplate.HookContext) error {
+							stages = append(stages, "pre-render grpc.name")
+							return nil
+						},
+					}),
+				),
+			},
+		},
+	}
+
+	values := gotemplate.NewOptionValues()
+	values.Base = gotemplate.OptionNameToValue{"projectName": "Some Project", "projectSlug": "some-project", "appName": "some", "moduleName": "github.com/user/some-project"}
+	values.Extensions["grpc"] = gotemplate.OptionNameToValue{"base": true, "name": "Greeter"}
+
+	tmpDir := t.TempDir()
+	err := gt.InitNewProject(context.Background(), &gotemplate.NewRepositoryOptions{OutputDir: tmpDir, OptionValues: values})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"pre-render grpc.name",
+		"pre-render grpc.base",
+		"render-file README.md",
+		"render-file main.go",
+		"post-render",
+		// go version, go mod init, go mod tidy and git init
+		"post-init some-project after 4 commands",
+	}, stages)
+
+	readme, err := os.ReadFile(filepath.Join(tmpDir, "some-project", "README.md"))
+	require.NoError(t, err)
+	require.Equal(t, "# GreeterService\ngenerated\n", string(readme))
+
+	mainGo, err := os.ReadFile(filepath.Join(tmpDir, "some-project", "main.go"))
+	require.NoError(t, err)
+	require.Equal(t, "package main\n\nfunc generated() {}\n", string(mainGo))
+
+	_, err = os.Stat(filepath.Join(tmpDir, "some-project", "hook.txt"))
+	require.NoError(t, err)
+}
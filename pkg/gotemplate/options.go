@@ -2,13 +2,13 @@ package gotemplate
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"os"
 	"os/exec"
-	"path"
 	"regexp"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/schwarzit/go-template/pkg/repos"
 )
 
@@ -35,6 +35,21 @@ func (e *ErrInvalidPattern) Error() string {
 	return fmt.Sprintf("%s: invalid pattern (expected %s (pattern: %s))", e.Value, e.Description, e.Pattern)
 }
 
+// ErrInvalidChoice indicates that a value is not one of the choices of an option.
+type ErrInvalidChoice struct {
+	Value   interface{}
+	Choices []Choice
+}
+
+func (e *ErrInvalidChoice) Error() string {
+	values := make([]string, 0, len(e.Choices))
+	for _, choice := range e.Choices {
+		values = append(values, fmt.Sprint(choice.Value))
+	}
+
+	return fmt.Sprintf("%v: invalid choice (expected one of %s)", e.Value, strings.Join(values, ", "))
+}
+
 // Validator is a single method interface that validates that a given value is valid.
 // If any error happens during validation or if the value is not valid an error will be returned.
 type Validator interface {
@@ -48,28 +63,58 @@ func (f ValidatorFunc) Validate(value interface{}) error {
 	return f(value)
 }
 
+// DescribedValidator is a Validator that can describe the values it accepts, e.g. for the docs.
+type DescribedValidator interface {
+	Validator
+	Describe() string
+}
+
 // Option is a struct containing all needed configuration for options to customize the template.
 type Option struct {
 	// name is the name of the option that will be used to reference it and also that will be shown on the cli.
 	name string
 	// description is the description of the option that should be shown.
 	// It's a StringValuer since it could depend on some earlier input.
-	description string
+	description StringValuer
+	// help is a longer explanation that is shown if "?" is entered in interactive mode.
+	help StringValuer
 	// defaultValue is the default value of the option.
 	// It's a Valuer since it could be depend on earlier inputs or some http call.
 	defaultValue Valuer
+	// defaultDescription describes how a dynamic default value is computed, e.g. for the docs.
+	defaultDescription string
+	// example is used instead of the default value in examples if the default depends on the environment.
+	example interface{}
 	// validator is used to validate an input value if it can be used as the value for this option.
 	// If it is not set it will by default by valid.
 	validator Validator
+	// choices are all values the option can be set to.
+	// If it is set any other value is invalid.
+	choices []Choice
 	// shouldDisplay decides whether the option is shown when the values are loaded interactively.
 	// In most cases this is used to ensure options are only shown if needed values have been supplied earlier.
 	// If it is not set it will by default be shown.
 	shouldDisplay BoolValuer
+	// requires are relations that have to hold if the option is enabled.
+	// If they don't hold the option is not shown either.
+	requires []Relation
+	// conflictsWith are relations that must not hold if the option is enabled.
+	conflictsWith []Relation
+	// valuesValidator is used to validate an input value depending on the values of other options.
+	valuesValidator ValuesValidator
 	// postHook is some function that will be executed after all options are loaded.
 	// This can for example be used to remove files from the created project folder or initialize tools based on inputs.
 	// The passed interface contains the value of the option for convenience (technically also contained in optionValues)
 	// targetDir indicates the working directory of the postHook
 	postHook PostHookFunc
+	// hooks are run at different stages of generating the project, see HookStage.
+	hooks []Hook
+}
+
+// Choice is a value an option can be set to together with a description of its meaning.
+type Choice struct {
+	Value       interface{}
+	Description string
 }
 
 type PostHookFunc func(value interface{}, optionValues *OptionValues, targetDir string) error
@@ -77,7 +122,7 @@ type PostHookFunc func(value interface{}, optionValues *OptionValues, targetDir
 func NewOption(name, description string, defaultValue Valuer, opts ...NewOptionOption) Option {
 	option := Option{
 		name:         name,
-		description:  description,
+		description:  StringValue(description),
 		defaultValue: defaultValue,
 	}
 
@@ -96,6 +141,61 @@ func WithValidator(validator Validator) NewOptionOption {
 	}
 }
 
+// WithDescription replaces the static description of the option, e.g. with one depending on earlier inputs.
+func WithDescription(description StringValuer) NewOptionOption {
+	return func(o *Option) {
+		o.description = description
+	}
+}
+
+// WithDefaultDescription describes how a dynamic default value is computed, e.g. for the docs.
+func WithDefaultDescription(description string) NewOptionOption {
+	return func(o *Option) {
+		o.defaultDescription = description
+	}
+}
+
+// WithExample sets a value that is used in examples instead of the default, if the default depends on the environment.
+func WithExample(example interface{}) NewOptionOption {
+	return func(o *Option) {
+		o.example = example
+	}
+}
+
+// WithHelp sets a longer explanation of the option that is shown if "?" is entered in interactive mode.
+func WithHelp(help StringValuer) NewOptionOption {
+	return func(o *Option) {
+		o.help = help
+	}
+}
+
+func WithChoices(choices ...Choice) NewOptionOption {
+	return func(o *Option) {
+		o.choices = choices
+	}
+}
+
+// WithValuesValidator sets a validator that gets the values of all earlier options next to the value.
+func WithValuesValidator(validator ValuesValidator) NewOptionOption {
+	return func(o *Option) {
+		o.valuesValidator = validator
+	}
+}
+
+// WithRequires adds relations that have to hold if the option is enabled.
+func WithRequires(relations ...Relation) NewOptionOption {
+	return func(o *Option) {
+		o.requires = append(o.requires, relations...)
+	}
+}
+
+// WithConflictsWith adds relations that must not hold if the option is enabled.
+func WithConflictsWith(relations ...Relation) NewOptionOption {
+	return func(o *Option) {
+		o.conflictsWith = append(o.conflictsWith, relations...)
+	}
+}
+
 func WithShouldDisplay(shouldDisplay BoolValuer) NewOptionOption {
 	return func(o *Option) {
 		o.shouldDisplay = shouldDisplay
@@ -108,12 +208,50 @@ func WithPosthook(postHook PostHookFunc) NewOptionOption {
 	}
 }
 
+// WithHook adds a hook that is run at hook.Stage if the option has a value.
+func WithHook(hook Hook) NewOptionOption {
+	return func(o *Option) {
+		o.hooks = append(o.hooks, hook)
+	}
+}
+
 func (s *Option) Name() string {
 	return s.name
 }
 
-func (s *Option) Description() string {
-	return s.description
+// Description returns the description of the option (possibly calculated with currentValues).
+func (s *Option) Description(currentValues *OptionValues) string {
+	if s.description == nil {
+		return ""
+	}
+
+	return s.description.Value(currentValues)
+}
+
+// StaticDescription returns the description of the option that doesn't depend on any input.
+// For dynamic descriptions this is their fallback.
+func (s *Option) StaticDescription() string {
+	return staticString(s.description)
+}
+
+// Help returns the longer explanation of the option (possibly calculated with currentValues), empty if there is none.
+func (s *Option) Help(currentValues *OptionValues) string {
+	if s.help == nil {
+		return ""
+	}
+
+	return s.help.Value(currentValues)
+}
+
+// StaticHelp returns the help of the option that doesn't depend on any input.
+func (s *Option) StaticHelp() string {
+	return staticString(s.help)
+}
+
+// Choices returns all values the option can be set to.
+// It is empty if the option is not restricted to a set of values.
+func (s *Option) Choices() []Choice {
+	return s.choices
 }
 
 // Default either returns the default value (possibly calculated with currentValues).
@@ -121,9 +259,26 @@ func (s *Option) Default(currentValues *OptionValues) interface{} {
 	return s.defaultValue.Value(currentValues)
 }
 
+// DefaultContext returns the default value like Default but passes ctx to ContextValues.
+// If a ContextValue falls back to its Fallback the reason is returned next to it.
+func (s *Option) DefaultContext(ctx context.Context, currentValues *OptionValues) (interface{}, error) {
+	if contextValue, ok := s.defaultValue.(*ContextValue); ok {
+		return contextValue.ValueContext(ctx, currentValues)
+	}
+
+	return s.Default(currentValues), nil
+}
+
 // ShouldDisplay returns a bool value indicating whether the option should be shown or not.
+// Options whose requirements don't hold are not shown.
 // If shouldDisplay variable is not set on the option true is returned.
 func (s *Option) ShouldDisplay(currentValues *OptionValues) bool {
+	for _, relation := range s.requires {
+		if _, ok := relation.holds(currentValues); !ok {
+			return false
+		}
+	}
+
 	if s.shouldDisplay != nil {
 		return s.shouldDisplay.Value(currentValues)
 	}
@@ -131,8 +286,12 @@ func (s *Option) ShouldDisplay(currentValues *OptionValues) bool {
 	return true
 }
 
-// Validate validates the value if a validator is specified.
+// Validate validates that the value is one of the choices and runs the validator if any is specified.
 func (s *Option) Validate(value interface{}) error {
+	if len(s.choices) > 0 && !s.isChoice(value) {
+		return &ErrInvalidChoice{Value: value, Choices: s.choices}
+	}
+
 	if s.validator != nil {
 		return s.validator.Validate(value)
 	}
@@ -140,6 +299,27 @@ func (s *Option) Validate(value interface{}) error {
 	return nil
 }
 
+// choiceDescription returns the description of the choice with value.
+func (s *Option) choiceDescription(value interface{}) string {
+	for _, choice := range s.choices {
+		if choice.Value == value {
+			return choice.Description
+		}
+	}
+
+	return fmt.Sprint(value)
+}
+
+func (s *Option) isChoice(value interface{}) bool {
+	for _, choice := range s.choices {
+		if choice.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 // PostHook executes the registered postHook if there is any.
 func (s *Option) PostHook(v interface{}, optionValues *OptionValues, targetDir string) error {
 	if s.postHook != nil {
@@ -149,12 +329,42 @@ func (s *Option) PostHook(v interface{}, optionValues *OptionValues, targetDir s
 	return nil
 }
 
+// allHooks returns all hooks of the option.
+// The postHook is run as HookPostRender hook after all others of the option.
+func (s *Option) allHooks() []Hook {
+	if s.postHook == nil {
+		return s.hooks
+	}
+
+	postHook := Hook{
+		Stage: HookPostRender,
+		Run: func(hc *HookContext) error {
+			return s.PostHook(hc.Value, hc.OptionValues, hc.Dir)
+		},
+	}
+
+	return append(append([]Hook{}, s.hooks...), postHook)
+}
+
 // Category is used to wrap multiple extensions into one organizational unit.
 // This is to reduce the amount of required user input if certain categories if extensions
 // can be skipped as a category instead of needing to skip all one by one.
 type Category struct {
-	Name    string
-	Options []Option
+	Name string
+	// Question is asked in interactive mode to decide whether the category's options are configured
+	// or all of them are set to their defaults. If it is empty "Customize <Name>?" is asked.
+	// Since skipping keeps the defaults, which might enable things, it should not read like enabling the category.
+	Question string
+	Options  []Option
+}
+
+// EnableQuestion returns the question that is asked to decide whether the category is configured.
+func (c *Category) EnableQuestion() string {
+	if c.Question != "" {
+		return c.Question
+	}
+
+	return fmt.Sprintf("Customize %s?", c.Name)
 }
 
 // Options is the main struct wrapping the configuration
@@ -166,6 +376,24 @@ type Options struct {
 	Extensions []Category
 }
 
+// PrefetchDefaults starts computing the Independent ContextValue defaults of all options in the background,
+// e.g. while the user answers the questions for earlier options.
+func (o *Options) PrefetchDefaults(ctx context.Context) {
+	for i := range o.Base {
+		if contextValue, ok := o.Base[i].defaultValue.(*ContextValue); ok {
+			contextValue.Prefetch(ctx)
+		}
+	}
+
+	for _, category := range o.Extensions {
+		for i := range category.Options {
+			if contextValue, ok := category.Options[i].defaultValue.(*ContextValue); ok {
+				contextValue.Prefetch(ctx)
+			}
+		}
+	}
+}
+
 // OptionValues is a struct mirroring the structure of Options but using maps.
 // Instead of the whole option only the set value of the option is kept.
 // This makes looking up already supplied option values easier than it would
@@ -185,14 +413,16 @@ func NewOptionValues() *OptionValues {
 type OptionNameToValue map[string]interface{}
 
 // NewOptions returns all of go/template's options.
-// Keeping repos.GithubTagLister in case it's needed in the future
-func NewOptions(_ repos.GithubTagLister) *Options { //nolint:funlen,cyclop // Static initialization
-	return &Options{
+// Keeping repos.TagLister in case it's needed in the future
+func NewOptions(_ repos.TagLister) *Options { //nolint:funlen,cyclop // Static initialization
+	var options *Options
+
+	options = &Options{
 		Base: []Option{
 			{
 				name:         "projectName",
 				defaultValue: StaticValue("Awesome Project"),
-				description:  "Name of the project",
+				description:  StringValue("Name of the project"),
 			},
 			{
 				name: "projectSlug",
@@ -200,23 +430,25 @@ func NewOptions(_ repos.GithubTagLister) *Options { //nolint:funlen,cyclop // St
 					projectName := ov.Base["projectName"].(string)
 					return strings.ReplaceAll(strings.ToLower(projectName), " ", "-")
 				}),
-				description: "Technical name of the project for folders and names. This will also be used as output directory.",
-				validator:   RegexValidator(`^[a-z1-9]+(-[a-z1-9]+)*$`, "only lowercase letters, numbers and dashes"),
+				defaultDescription: `"projectName" in lowercase with dashes instead of spaces`,
+				description:        StringValue("Technical name of the project for folders and names. This will also be used as output directory."),
+				validator:          PatternValidator{Pattern: `^[a-z1-9]+(-[a-z1-9]+)*$`, Description: "only lowercase letters, numbers and dashes"},
 			},
 			{
 				name:         "projectDescription",
 				defaultValue: StaticValue("The awesome project provides awesome features to awesome people."),
-				description:  "Description of the project used in the README.",
+				description:  StringValue("Description of the project used in the README."),
 			},
 			{
 				name: "appName",
 				defaultValue: DynamicValue(func(ov *OptionValues) interface{} {
 					return ov.Base["projectSlug"].(string)
 				}),
-				description: `The name of the binary that you want to create.
+				defaultDescription: `"projectSlug"`,
+				description: StringValue(`The name of the binary that you want to create.
 Could be the same as your "projectSlug" but since Go supports multiple apps in one repo it could also be sth. else.
-For example if your project is for some API there could be one app for the server and one CLI client.`,
-				validator: RegexValidator(`^[a-z1-9]+(-[a-z1-9]+)*$`, "only lowercase letters, numbers and dashes"),
+For example if your project is for some API there could be one app for the server and one CLI client.`),
+				validator: PatternValidator{Pattern: `^[a-z1-9]+(-[a-z1-9]+)*$`, Description: "only lowercase letters, numbers and dashes"},
 			},
 			{
 				name: "moduleName",
@@ -224,195 +456,217 @@ For example if your project is for some API there could be one app for the serve
 					projectSlug := vals.Base["projectSlug"].(string)
 					return fmt.Sprintf("github.com/user/%s", projectSlug)
 				}),
-				description: `The name of the Go module defined in the "go.mod" file.
-This is used if you want to "go get" the module.
+				defaultDescription: `"github.com/user/<projectSlug>"`,
+				description: FallbackStringValue{
+					Fallback: `The name of the Go module defined in the "go.mod" file.`,
+					Dynamic: func(vals *OptionValues) string {
+						// the CI provider is asked later, so its default is used unless it is set already (e.g. by a preset)
+						ciProvider := options.lookup("ci.provider")
+						provider, ok := vals.Extensions["ci"]["provider"].(int)
+						if !ok {
+							provider, _ = ciProvider.Default(vals).(int)
+						}
+
+						description := fmt.Sprintf(`The name of the Go module defined in the "go.mod" file, e.g. %q`, moduleNameExample(provider, vals.Base["projectSlug"]))
+						if provider != 0 { // 0 == no CI
+							description += " for " + ciProvider.choiceDescription(provider)
+						}
+
+						return description + "."
+					},
+				},
+				help: StringValue(`This is used if you want to "go get" the module.
 Please be aware that this depends on your version control system.
-The default points to "github.com" but for devops for example it would look sth. like this "dev.azure.com/org/project/repo.git"`,
-				validator: RegexValidator(`^[\S]+$`, "no whitespaces"),
+The default points to "github.com" but for devops for example it would look sth. like this "dev.azure.com/org/project/repo.git"`),
+				validator: PatternValidator{Pattern: `^[\S]+$`, Description: "no whitespaces"},
 			},
 		},
 		Extensions: []Category{
 			{
-				Name: "openSource",
+				Name:     "openSource",
+				Question: "Customize the open source files (license and codeowners)?",
 				Options: []Option{
 					{
 						name:         "license",
 						defaultValue: StaticValue(1),
-						description: `Set an OpenSource license.
-Unsure which to pick? Checkout Github's https://choosealicense.com/
-Options:
-	0: Add no license
-	1: MIT License
-	2: Apache License 2.0
-	3: GNU AGPLv3
-	4: GNU GPLv3
-	5: GNU LGPLv3
-	6: Mozilla Public License 2.0
-	7: Boost Software License 1.0
-	8: The Unlicense`,
-						postHook: func(v interface{}, _ *OptionValues, targetDir string) error {
-							if v.(int) == 0 {
-								files := []string{"LICENSE", "CODEOWNERS"}
-								for _, file := range files {
-									if err := os.RemoveAll(path.Join(targetDir, file)); err != nil {
-										return err
-									}
-								}
-							}
-							return nil
+						description: StringValue(`Set an OpenSource license.
+Unsure which to pick? Checkout Github's https://choosealicense.com/`),
+						choices: []Choice{
+							{Value: 0, Description: "Add no license"},
+							{Value: 1, Description: "MIT License"},
+							{Value: 2, Description: "Apache License 2.0"},
+							{Value: 3, Description: "GNU AGPLv3"},
+							{Value: 4, Description: "GNU GPLv3"},
+							{Value: 5, Description: "GNU LGPLv3"},
+							{Value: 6, Description: "Mozilla Public License 2.0"},
+							{Value: 7, Description: "Boost Software License 1.0"},
+							{Value: 8, Description: "The Unlicense"},
 						},
 					},
 					{
 						name: "author",
-						defaultValue: DynamicValue(func(vals *OptionValues) interface{} {
-							buffer := &bytes.Buffer{}
-							gitName := exec.Command("git", "config", "--get", "user.name")
-							gitName.Stdout = buffer
-							if err := gitName.Run(); err != nil || len(buffer.Bytes()) == 0 {
-								return "Marty Mc Fly"
-							}
-							return strings.TrimSpace(buffer.String())
-						}),
-						description: `License author`,
-						shouldDisplay: DynamicBoolValue(func(vals *OptionValues) bool {
-							switch vals.Extensions["openSource"]["license"].(int) {
-							case 1, 2: //nolint:gomnd // 1: MIT License; 2: Apache License 2.0
-								return true
-							}
-							return false
-						}),
+						defaultValue: &ContextValue{
+							Compute: func(ctx context.Context, _ *OptionValues) (interface{}, error) {
+								return gitConfig(ctx, "user.name")
+							},
+							Fallback:    "Marty Mc Fly",
+							Independent: true,
+						},
+						description:        StringValue(`License author`),
+						defaultDescription: `"git config user.name"`,
+						example:            "Marty Mc Fly",
+						// 1: MIT License; 2: Apache License 2.0
+						requires: []Relation{{Option: "openSource.license", OneOf: []interface{}{1, 2}}},
 					},
 					{
 						name: "codeowner",
-						defaultValue: DynamicValue(func(vals *OptionValues) interface{} {
-							buffer := &bytes.Buffer{}
-							gitMail := exec.Command("git", "config", "--get", "user.email")
-							gitMail.Stdout = buffer
-							if err := gitMail.Run(); err != nil || len(buffer.Bytes()) == 0 {
-								return "Marty.Mc.Fly@future.back"
-							}
-							return strings.TrimSpace(buffer.String())
-						}),
-						description: "Set the codeowner of the project",
-						shouldDisplay: DynamicBoolValue(func(vals *OptionValues) bool {
-							return vals.Extensions["openSource"]["license"].(int) != 0 // 0 == no license
-						}),
+						defaultValue: &ContextValue{
+							Compute: func(ctx context.Context, _ *OptionValues) (interface{}, error) {
+								return gitConfig(ctx, "user.email")
+							},
+							Fallback:    "Marty.Mc.Fly@future.back",
+							Independent: true,
+						},
+						description:        StringValue("Set the codeowner of the project"),
+						defaultDescription: `"git config user.email"`,
+						example:            "Marty.Mc.Fly@future.back",
+						// 0 == no license
+						requires: []Relation{{Option: "openSource.license"}},
 					},
 				},
 			},
 			{
-				Name: "ci",
+				Name:     "ci",
+				Question: "Customize the CI pipeline?",
 				Options: []Option{
 					{
 						name:         "provider",
 						defaultValue: StaticValue(1),
-						description: `Set an CI pipeline provider integration
-			Options:
-			0: No CI
-			1: Github
-			2: Gitlab
-			3: Azure DevOps`,
-						postHook: func(v interface{}, _ *OptionValues, targetDir string) error {
-							ciFiles := map[int][]string{
-								0: {},
-								1: {".github"},
-								2: {".gitlab-ci.yml"},
-								3: {".azure-pipelines.yml"},
-							}
-
-							for i, files := range ciFiles {
-								if i == v.(int) {
-									continue
-								}
-								for _, file := range files {
-									if err := os.RemoveAll(path.Join(targetDir, file)); err != nil {
-										return err
-									}
-								}
-							}
-							return nil
+						description:  StringValue("Set an CI pipeline provider integration"),
+						choices: []Choice{
+							{Value: 0, Description: "No CI"},
+							{Value: 1, Description: "Github"},
+							{Value: 2, Description: "Gitlab"},
+							{Value: 3, Description: "Azure DevOps"},
 						},
 					},
 				},
 			},
 			{
-				Name: "grpc",
+				Name:     "grpc",
+				Question: "Customize the gRPC setup?",
 				Options: []Option{
 					{
 						name:         "base",
 						defaultValue: StaticValue(false),
-						description:  "Base configuration for gRPC",
-						postHook: func(v interface{}, _ *OptionValues, targetDir string) error {
-							set := v.(bool)
-							files := []string{"api/proto", "buf.gen.yaml", "buf.work.yaml", "api/openapi.v1.yml"}
-
-							if set {
-								return os.RemoveAll(path.Join(targetDir, "api/openapi.v1.yml"))
-							}
-							return removeAllBut(targetDir, files, "api/openapi.v1.yml")
-						},
+						description:  StringValue("Base configuration for gRPC"),
 					},
 					{
 						name:         "grpcGateway",
 						defaultValue: StaticValue(false),
-						description:  "Extend gRPC configuration with grpc-gateway",
-						shouldDisplay: DynamicBoolValue(func(vals *OptionValues) bool {
-							return vals.Extensions["grpc"]["base"].(bool)
-						}),
+						description:  StringValue("Extend gRPC configuration with grpc-gateway"),
+						requires:     []Relation{{Option: "grpc.base"}},
 					},
 				},
 			},
 		},
 	}
+
+	return options
 }
 
-// removeAllBut removes all files in the toRemove slice except for the exception.
-func removeAllBut(targetDir string, toRemove []string, exception string) error {
-	for _, item := range toRemove {
-		if item == exception {
-			continue
-		}
+// gitConfig returns the value of key (e.g. "user.name") in the git config.
+func gitConfig(ctx context.Context, key string) (interface{}, error) {
+	buffer := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "git", "config", "--get", key)
+	cmd.Stdout = buffer
 
-		if err := os.RemoveAll(path.Join(targetDir, item)); err != nil {
-			return err
-		}
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "git config %s", key)
 	}
 
-	return nil
+	value := strings.TrimSpace(buffer.String())
+	if value == "" {
+		return nil, errors.Errorf("git config %s is empty", key)
+	}
+
+	return value, nil
+}
+
+// moduleNameExample returns an example module name for a project on the platform of the CI provider.
+func moduleNameExample(provider int, projectSlug interface{}) string {
+	switch provider {
+	case 2: //nolint:gomnd // Gitlab
+		return fmt.Sprintf("gitlab.com/group/%v", projectSlug)
+	case 3: //nolint:gomnd // Azure DevOps
+		return fmt.Sprintf("dev.azure.com/org/project/%v.git", projectSlug)
+	default:
+		return fmt.Sprintf("github.com/user/%v", projectSlug)
+	}
 }
 
 // RangeValidator validates that value is in between or equal to min and max.
 func RangeValidator(min, max int) ValidatorFunc {
-	return func(value interface{}) error {
-		val := value.(int)
-
-		if val < min || val > max {
-			return &ErrOutOfRange{
-				Value: val,
-				Min:   min,
-				Max:   max,
-			}
-		}
-
-		return nil
-	}
+	return ValueRange{Min: min, Max: max}.Validate
 }
 
 // RegexValidator returns a ValidatorFunc to validate a given value against a regex pattern.
 // If the pattern doesn't match a ErrInvalidPattern is returned with a description on what the pattern means.
 func RegexValidator(pattern, description string) ValidatorFunc {
-	return func(value interface{}) error {
-		str := value.(string)
+	return PatternValidator{Pattern: pattern, Description: description}.Validate
+}
 
-		matched, err := regexp.MatchString(pattern, str)
-		if err != nil {
-			return err
-		}
+var (
+	_ DescribedValidator = ValueRange{}
+	_ DescribedValidator = PatternValidator{}
+)
+
+// ValueRange is a DescribedValidator that validates that an int value is in between or equal to Min and Max.
+type ValueRange struct {
+	Min int
+	Max int
+}
+
+func (r ValueRange) Validate(value interface{}) error {
+	val := value.(int)
 
-		if !matched {
-			return &ErrInvalidPattern{Value: str, Pattern: pattern, Description: description}
+	if val < r.Min || val > r.Max {
+		return &ErrOutOfRange{
+			Value: val,
+			Min:   r.Min,
+			Max:   r.Max,
 		}
+	}
 
-		return nil
+	return nil
+}
+
+func (r ValueRange) Describe() string {
+	return fmt.Sprintf("%d to %d", r.Min, r.Max)
+}
+
+// PatternValidator is a DescribedValidator that validates a string value against a regex pattern.
+// Description explains the pattern to users.
+type PatternValidator struct {
+	Pattern     string
+	Description string
+}
+
+func (v PatternValidator) Validate(value interface{}) error {
+	str := value.(string)
+
+	matched, err := regexp.MatchString(v.Pattern, str)
+	if err != nil {
+		return err
+	}
+
+	if !matched {
+		return &ErrInvalidPattern{Value: str, Pattern: v.Pattern, Description: v.Description}
 	}
+
+	return nil
+}
+
+func (v PatternValidator) Describe() string {
+	return fmt.Sprintf("%s (pattern: %s)", v.Description, v.Pattern)
 }
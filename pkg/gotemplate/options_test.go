@@ -75,3 +75,35 @@ func Test_RegexValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestOption_Validate(t *testing.T) {
+	option := NewOption("provider", "CI provider", StaticValue(1), WithChoices(
+		Choice{Value: 0, Description: "No CI"},
+		Choice{Value: 1, Description: "Github"},
+	))
+
+	assert.NoError(t, option.Validate(0))
+	assert.Equal(t, &ErrInvalidChoice{Value: 2, Choices: option.Choices()}, option.Validate(2))
+}
+
+func TestOption_Description(t *testing.T) {
+	options := NewOptions(nil)
+	moduleName := options.lookup("moduleName")
+
+	values := &OptionValues{Base: OptionNameToValue{"projectSlug": "my-project"}}
+	assert.Equal(t, `The name of the Go module defined in the "go.mod" file, e.g. "github.com/user/my-project" for Github.`, moduleName.Description(values))
+
+	values.Extensions = map[string]OptionNameToValue{"ci": {"provider": 2}}
+	assert.Equal(t, `The name of the Go module defined in the "go.mod" file, e.g. "gitlab.com/group/my-project" for Gitlab.`, moduleName.Description(values))
+
+	assert.Equal(t, `The name of the Go module defined in the "go.mod" file.`, moduleName.StaticDescription())
+	assert.Contains(t, moduleName.StaticHelp(), `"go get"`)
+
+	// dynamic descriptions without fallback have no static description
+	option := NewOption("option", "", StaticValue(""), WithDescription(DynamicStringValue(func(vals *OptionValues) string {
+		return "dynamic"
+	})))
+	assert.Equal(t, "dynamic", option.Description(NewOptionValues()))
+	assert.Empty(t, option.StaticDescription())
+	assert.Empty(t, option.Help(NewOptionValues()))
+}
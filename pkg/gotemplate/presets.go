@@ -0,0 +1,312 @@
+package gotemplate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// presetsDirEnv points to a directory with the presets of an organisation.
+	presetsDirEnv = "GT_PRESETS_DIR"
+	// builtinPresetSource is the source of the presets that come with gt.
+	builtinPresetSource = "built-in"
+)
+
+var ErrUnknownPreset = errors.New("unknown preset")
+
+// Preset is a named bundle of option values for a common shape of projects.
+// Its values are set before the remaining values are loaded interactively or from a file.
+type Preset struct {
+	Name        string
+	Description string
+	// Source is the file the preset was loaded from or "built-in".
+	Source string
+	// Values only contains the options the preset sets.
+	Values *OptionValues
+}
+
+// presetFile is the format of preset files, which are values files with an additional description.
+type presetFile struct {
+	Description  string `yaml:"description"`
+	OptionValues `yaml:",inline"`
+}
+
+// BuiltinPresets returns the presets that come with gt.
+func BuiltinPresets() []Preset {
+	return []Preset{
+		{
+			Name:        "grpc-service",
+			Description: "gRPC microservice with grpc-gateway",
+			Source:      builtinPresetSource,
+			Values: &OptionValues{
+				Base:       OptionNameToValue{},
+				Extensions: map[string]OptionNameToValue{"grpc": {"base": true, "grpcGateway": true}},
+			},
+		},
+		{
+			Name:        "cli",
+			Description: "Internal CLI tool",
+			Source:      builtinPresetSource,
+			Values: &OptionValues{
+				Base: OptionNameToValue{},
+				Extensions: map[string]OptionNameToValue{
+					"openSource": {"license": 0},
+					"grpc":       {"base": false},
+				},
+			},
+		},
+		{
+			Name:        "library",
+			Description: "Open-source library",
+			Source:      builtinPresetSource,
+			Values: &OptionValues{
+				Base: OptionNameToValue{},
+				Extensions: map[string]OptionNameToValue{
+					"openSource": {"license": 1},
+					"grpc":       {"base": false},
+				},
+			},
+		},
+	}
+}
+
+// DefaultPresetDirs returns the directories presets are loaded from besides the built-in ones:
+// the organisation's directory set in $GT_PRESETS_DIR and the user's directory "gt/presets" in the config dir.
+func DefaultPresetDirs() []string {
+	var dirs []string
+
+	if dir := os.Getenv(presetsDirEnv); dir != "" {
+		dirs = append(dirs, dir)
+	}
+
+	if dir, err := userConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, "presets"))
+	}
+
+	return dirs
+}
+
+// userConfigDir returns gt's directory in $XDG_CONFIG_HOME or the OS specific config dir.
+func userConfigDir() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		var err error
+		if dir, err = os.UserConfigDir(); err != nil {
+			return "", err
+		}
+	}
+
+	return filepath.Join(dir, "gt"), nil
+}
+
+// LoadPresets returns the built-in presets and the presets in gt.PresetDirs.
+// Every "<name>.yml" file in the directories is a preset with the same format as values files
+// and an optional "description". Presets of later directories replace earlier ones with the same name.
+// Directories that don't exist are ignored.
+func (gt *GT) LoadPresets() ([]Preset, error) {
+	presets := BuiltinPresets()
+
+	for _, dir := range gt.PresetDirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.yml"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			preset, err := loadPreset(file)
+			if err != nil {
+				return nil, err
+			}
+
+			presets = replacePreset(presets, preset)
+		}
+	}
+
+	return presets, nil
+}
+
+func loadPreset(file string) (Preset, error) {
+	fileBytes, err := os.ReadFile(file)
+	if err != nil {
+		return Preset{}, err
+	}
+
+	var content presetFile
+	if err := yaml.Unmarshal(fileBytes, &content); err != nil {
+		return Preset{}, errors.Wrapf(err, "preset %s", file)
+	}
+
+	values := content.OptionValues
+	if values.Base == nil {
+		values.Base = OptionNameToValue{}
+	}
+
+	if values.Extensions == nil {
+		values.Extensions = map[string]OptionNameToValue{}
+	}
+
+	return Preset{
+		Name:        strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)),
+		Description: content.Description,
+		Source:      file,
+		Values:      &values,
+	}, nil
+}
+
+func replacePreset(presets []Preset, preset Preset) []Preset {
+	for i := range presets {
+		if presets[i].Name == preset.Name {
+			presets[i] = preset
+			return presets
+		}
+	}
+
+	return append(presets, preset)
+}
+
+// SelectPreset loads all presets and sets gt.Preset to the one called name.
+// Options the preset sets that don't exist are reported like unknown keys in values files.
+func (gt *GT) SelectPreset(name string) error {
+	presets, err := gt.LoadPresets()
+	if err != nil {
+		return err
+	}
+
+	for i := range presets {
+		if presets[i].Name != name {
+			continue
+		}
+
+		unknown := gt.Options.unknownOptionKeys(presets[i].Values)
+		for j := range unknown {
+			unknown[j] = fmt.Sprintf("preset %s: %s", name, unknown[j])
+		}
+
+		if err := gt.reportUnknownKeys(unknown); err != nil {
+			return err
+		}
+
+		gt.Preset = &presets[i]
+
+		return nil
+	}
+
+	names := make([]string, 0, len(presets))
+	for _, preset := range presets {
+		names = append(names, preset.Name)
+	}
+
+	return errors.Wrapf(ErrUnknownPreset, "%q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// presetValue returns the value gt.Preset sets for the option referenced by key after coercing and validating it.
+func (gt *GT) presetValue(key string, option *Option, optionValues *OptionValues) (interface{}, bool, error) {
+	if gt.Preset == nil {
+		return nil, false, nil
+	}
+
+	// like in values files null means the option is not set
+	value, ok := gt.Preset.Values.get(key)
+	if !ok || value == nil {
+		return nil, false, nil
+	}
+
+	value, err := coerceFileValue(key, value, option.Default(optionValues))
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "preset %s", gt.Preset.Name)
+	}
+
+	if err := gt.Options.validateFileOption(key, option, value, *optionValues); err != nil {
+		return nil, false, errors.Wrapf(err, "preset %s", gt.Preset.Name)
+	}
+
+	return value, true, nil
+}
+
+// addPresetValues adds the values of gt.Preset to optionValues that are not set already.
+func (gt *GT) addPresetValues(optionValues *OptionValues) {
+	if gt.Preset == nil {
+		return
+	}
+
+	for name, value := range gt.Preset.Values.Base {
+		if _, ok := optionValues.Base[name]; !ok {
+			if optionValues.Base == nil {
+				optionValues.Base = OptionNameToValue{}
+			}
+
+			optionValues.Base[name] = value
+		}
+	}
+
+	for category, values := range gt.Preset.Values.Extensions {
+		for name, value := range values {
+			if _, ok := optionValues.Extensions[category][name]; ok {
+				continue
+			}
+
+			if optionValues.Extensions == nil {
+				optionValues.Extensions = map[string]OptionNameToValue{}
+			}
+
+			if optionValues.Extensions[category] == nil {
+				optionValues.Extensions[category] = OptionNameToValue{}
+			}
+
+			optionValues.Extensions[category][name] = value
+		}
+	}
+}
+
+// presetSetsCategory returns whether gt.Preset sets any option of category.
+func (gt *GT) presetSetsCategory(category string) bool {
+	return gt.Preset != nil && len(gt.Preset.Values.Extensions[category]) > 0
+}
+
+// Settings returns "<key>: <value>" for every option the preset sets, base options first.
+func (p *Preset) Settings() []string {
+	var settings []string
+
+	for _, name := range sortedKeys(p.Values.Base) {
+		settings = append(settings, fmt.Sprintf("%s: %v", name, p.Values.Base[name]))
+	}
+
+	for _, category := range sortedKeys(p.Values.Extensions) {
+		for _, name := range sortedKeys(p.Values.Extensions[category]) {
+			settings = append(settings, fmt.Sprintf("%s: %v", optionKey(category, name), p.Values.Extensions[category][name]))
+		}
+	}
+
+	return settings
+}
+
+// PrintPresets prints all presets together with the options they set.
+func (gt *GT) PrintPresets() error {
+	presets, err := gt.LoadPresets()
+	if err != nil {
+		return err
+	}
+
+	for i, preset := range presets {
+		if i > 0 {
+			gt.printf("\n")
+		}
+
+		gt.printf("%s (%s)\n", gt.cyanStyler().Bold().Styled(preset.Name), preset.Source)
+
+		if preset.Description != "" {
+			gt.printf("  %s\n", preset.Description)
+		}
+
+		for _, setting := range preset.Settings() {
+			gt.printf("    %s\n", setting)
+		}
+	}
+
+	return nil
+}
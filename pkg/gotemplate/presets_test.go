@@ -0,0 +1,108 @@
+package gotemplate
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGT_LoadPresets(t *testing.T) {
+	orgDir, userDir := t.TempDir(), t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(orgDir, "cli.yml"), []byte(`description: Company CLI
+extensions:
+  ci:
+    provider: 2
+`), permissionRW))
+	require.NoError(t, os.WriteFile(filepath.Join(orgDir, "team.yml"), []byte("base:\n  moduleName: gitlab.company.com/team/x\n"), permissionRW))
+	require.NoError(t, os.WriteFile(filepath.Join(userDir, "team.yml"), []byte("description: Mine\n"), permissionRW))
+
+	gt := &GT{PresetDirs: []string{orgDir, userDir, filepath.Join(userDir, "does-not-exist")}}
+
+	presets, err := gt.LoadPresets()
+	require.NoError(t, err)
+
+	var names []string
+	for _, preset := range presets {
+		names = append(names, preset.Name)
+	}
+
+	assert.Equal(t, []string{"grpc-service", "cli", "library", "team"}, names)
+	assert.Equal(t, "Company CLI", presets[1].Description)
+	assert.Equal(t, []string{"ci.provider: 2"}, presets[1].Settings())
+	// the user's preset replaces the organisation's one
+	assert.Equal(t, filepath.Join(userDir, "team.yml"), presets[3].Source)
+	assert.Empty(t, presets[3].Settings())
+}
+
+func TestGT_SelectPreset(t *testing.T) {
+	out := &bytes.Buffer{}
+	gt := &GT{Streams: Streams{Out: out, Err: out}, Options: NewOptions(nil)}
+
+	require.NoError(t, gt.SelectPreset("grpc-service"))
+	assert.Equal(t, "grpc-service", gt.Preset.Name)
+	assert.Empty(t, out.String())
+
+	err := gt.SelectPreset("grpc")
+	require.ErrorIs(t, err, ErrUnknownPreset)
+	require.ErrorContains(t, err, `"grpc" (available: grpc-service, cli, library)`)
+}
+
+func TestGT_LoadConfigValues_preset(t *testing.T) {
+	newGT := func(input string) *GT {
+		return &GT{
+			Streams: Streams{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, InScanner: bufio.NewScanner(strings.NewReader(input))},
+			Options: &Options{
+				Base: []Option{NewOption("name", "", StaticValue("default"))},
+				Extensions: []Category{
+					{
+						Name: "grpc",
+						Options: []Option{
+							NewOption("base", "", StaticValue(false)),
+							NewOption("gateway", "", StaticValue(false), WithRequires(Relation{Option: "grpc.base"})),
+						},
+					},
+				},
+			},
+			Preset: &Preset{Name: "test", Values: &OptionValues{
+				Base:       OptionNameToValue{},
+				Extensions: map[string]OptionNameToValue{"grpc": {"base": true, "gateway": "yes"}},
+			}},
+		}
+	}
+
+	t.Run("interactive mode only asks for values the preset doesn't set", func(t *testing.T) {
+		optionValues, err := newGT("name\n").LoadConfigValuesInteractively()
+		require.NoError(t, err)
+		assert.Equal(t, &OptionValues{
+			Base:       OptionNameToValue{"name": "name"},
+			Extensions: map[string]OptionNameToValue{"grpc": {"base": true, "gateway": true}},
+		}, optionValues)
+	})
+
+	t.Run("values of the file take precedence", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "values.yml")
+		require.NoError(t, os.WriteFile(file, []byte("extensions:\n  grpc:\n    gateway: false\n"), permissionRW))
+
+		optionValues, err := newGT("").LoadConfigValuesFromFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, &OptionValues{
+			Base:       OptionNameToValue{"name": "default"},
+			Extensions: map[string]OptionNameToValue{"grpc": {"base": true, "gateway": false}},
+		}, optionValues)
+	})
+
+	t.Run("invalid preset values are an error", func(t *testing.T) {
+		gt := newGT("name\n")
+		gt.Preset.Values.Extensions["grpc"]["base"] = false
+
+		_, err := gt.LoadConfigValuesInteractively()
+		require.ErrorContains(t, err, "preset test: grpc.gateway=true requires grpc.base to be enabled (it is false)")
+	})
+}
@@ -37,7 +37,16 @@ func (gt *GT) printWarningf(format string, a ...interface{}) {
 }
 
 func (gt *GT) printOption(opts *Option, optionValues *OptionValues) {
-	gt.printf("%s\n", gt.yellowStyler().Underline().Styled(opts.Description()))
+	gt.printf("%s\n", gt.yellowStyler().Underline().Styled(opts.Description(optionValues)))
+	if opts.Help(optionValues) != "" {
+		gt.printf("Enter \"?\" for more information.\n")
+	}
+	if choices := opts.Choices(); len(choices) > 0 {
+		gt.printf("Options:\n")
+		for _, choice := range choices {
+			gt.printf("\t%v: %s\n", choice.Value, choice.Description)
+		}
+	}
 	gt.printf("%s: (%v) ", gt.cyanStyler().Styled(opts.Name()), opts.Default(optionValues))
 }
 
@@ -56,3 +65,32 @@ func (gt *GT) printCategory(category string) {
 	gt.printf("| CATEGORY: %q\n", strings.ToUpper(category))
 	gt.printf(" --\n")
 }
+
+// printCategoryDefaults prints the values the options of a skipped category are set to,
+// so that it is visible what skipping enables.
+func (gt *GT) printCategoryDefaults(category *Category, optionValues *OptionValues) {
+	var defaults []string
+	for i := range category.Options {
+		option := &category.Options[i]
+
+		value, ok := optionValues.Extensions[category.Name][option.Name()]
+		if !ok || !option.ShouldDisplay(optionValues) {
+			continue
+		}
+
+		defaults = append(defaults, fmt.Sprintf("%s=%s", option.Name(), option.choiceDescription(value)))
+	}
+
+	if len(defaults) > 0 {
+		gt.printf("Using the defaults: %s\n\n", strings.Join(defaults, ", "))
+	}
+}
+
+func (gt *GT) printEnabledCategories(enabled []string) {
+	if len(enabled) == 0 {
+		gt.printProgressf("No categories customized, all extensions use their defaults.")
+		return
+	}
+
+	gt.printProgressf("Customized categories: %s", strings.Join(enabled, ", "))
+}
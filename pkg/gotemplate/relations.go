@@ -0,0 +1,236 @@
+package gotemplate
+
+import (
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ValuesValidator validates the value of an option depending on the values of other options.
+// Only options that come before the validated option are guaranteed to be set in optionValues.
+type ValuesValidator interface {
+	ValidateValues(value interface{}, optionValues *OptionValues) error
+}
+
+// ValuesValidatorFunc is a function implementing the ValuesValidator interface.
+type ValuesValidatorFunc func(value interface{}, optionValues *OptionValues) error
+
+func (f ValuesValidatorFunc) ValidateValues(value interface{}, optionValues *OptionValues) error {
+	return f(value, optionValues)
+}
+
+// Relation references another option and the value it needs to have.
+// Relations are only checked if the option they belong to is enabled (i.e. set to a non zero value),
+// defaults included. Options that are not shown and therefore keep their default are exempt.
+type Relation struct {
+	// Option is the referenced option, base options are referenced by their name, extension options by "<category>.<name>".
+	// It has to come before the option the relation belongs to.
+	Option string
+	// Value is the value the referenced option needs to have for the relation to hold.
+	// If it and OneOf are nil the referenced option needs to be enabled.
+	Value interface{}
+	// OneOf contains values the referenced option can have alternatively to Value.
+	OneOf []interface{}
+}
+
+// holds returns whether the referenced option has the value of the relation as well as its actual value.
+func (r Relation) holds(optionValues *OptionValues) (interface{}, bool) {
+	actual, ok := optionValues.get(r.Option)
+	if !ok {
+		return nil, false
+	}
+
+	if r.Value == nil && len(r.OneOf) == 0 {
+		return actual, isEnabled(actual)
+	}
+
+	if r.Value != nil && actual == r.Value {
+		return actual, true
+	}
+
+	for _, value := range r.OneOf {
+		if actual == value {
+			return actual, true
+		}
+	}
+
+	return actual, false
+}
+
+// expectation describes the values of the referenced option the relation holds for.
+func (r Relation) expectation() string {
+	var values []string
+	if r.Value != nil {
+		values = append(values, fmt.Sprint(r.Value))
+	}
+
+	for _, value := range r.OneOf {
+		values = append(values, fmt.Sprint(value))
+	}
+
+	switch len(values) {
+	case 0:
+		return "enabled"
+	case 1:
+		return values[0]
+	default:
+		return "one of " + strings.Join(values, ", ")
+	}
+}
+
+// ErrRelation indicates that a requirement of an option is not met or that it conflicts with another option.
+// The message names the other option that caused the failure.
+type ErrRelation struct {
+	// Option is the key of the option whose relation failed.
+	Option   string
+	Value    interface{}
+	Relation Relation
+	// Conflict is true if the relation was declared with WithConflictsWith, false for WithRequires.
+	Conflict bool
+	// Actual is the value of the referenced option, nil if it is not set.
+	Actual interface{}
+}
+
+func (e *ErrRelation) Error() string {
+	actual := "not set"
+	if e.Actual != nil {
+		actual = fmt.Sprint(e.Actual)
+	}
+
+	if e.Conflict {
+		return fmt.Sprintf("%s=%v conflicts with %s being %s (it is %s), change one of them",
+			e.Option, e.Value, e.Relation.Option, e.Relation.expectation(), actual)
+	}
+
+	return fmt.Sprintf("%s=%v requires %s to be %s (it is %s)", e.Option, e.Value, e.Relation.Option, e.Relation.expectation(), actual)
+}
+
+// isEnabled returns whether value is set to something else than its zero value.
+func isEnabled(value interface{}) bool {
+	return value != nil && !reflect.ValueOf(value).IsZero()
+}
+
+// optionKey returns the key an option is referenced by, see Relation.Option and Hook.After.
+func optionKey(category, name string) string {
+	if category == "" {
+		return name
+	}
+
+	return category + "." + name
+}
+
+// get returns the value of the option referenced by key, see Relation.Option.
+func (ov *OptionValues) get(key string) (interface{}, bool) {
+	category, name, ok := strings.Cut(key, ".")
+	if !ok {
+		value, ok := ov.Base[key]
+		return value, ok
+	}
+
+	value, ok := ov.Extensions[category][name]
+
+	return value, ok
+}
+
+// has returns whether there is an option referenced by key.
+func (o *Options) has(key string) bool {
+	return o.lookup(key) != nil
+}
+
+// lookup returns the option referenced by key, nil if there is none.
+func (o *Options) lookup(key string) *Option {
+	for i := range o.Base {
+		if o.Base[i].Name() == key {
+			return &o.Base[i]
+		}
+	}
+
+	for _, category := range o.Extensions {
+		for i := range category.Options {
+			if optionKey(category.Name, category.Options[i].Name()) == key {
+				return &category.Options[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// keys returns the keys of all options in their order.
+func (o *Options) keys() []string {
+	var keys []string
+
+	for i := range o.Base {
+		keys = append(keys, o.Base[i].Name())
+	}
+
+	for _, category := range o.Extensions {
+		for i := range category.Options {
+			keys = append(keys, optionKey(category.Name, category.Options[i].Name()))
+		}
+	}
+
+	return keys
+}
+
+// validateRelations checks that value of the option referenced by key meets its requirements,
+// doesn't conflict with other options and is accepted by its ValuesValidator.
+// Interactive mode, file mode and any other source of values have to validate values with it.
+func (o *Options) validateRelations(key string, option *Option, value interface{}, optionValues *OptionValues) error {
+	for _, relation := range append(append([]Relation{}, option.requires...), option.conflictsWith...) {
+		if !o.has(relation.Option) {
+			return errors.Wrapf(ErrMalformedInput, "%s has a relation to unknown option %q", key, relation.Option)
+		}
+	}
+
+	// options that are not shown are set to their defaults, which don't need to fulfill the relations then
+	hiddenDefault := !option.ShouldDisplay(optionValues) && value == option.Default(optionValues)
+
+	if isEnabled(value) && !hiddenDefault {
+		for _, relation := range option.requires {
+			if actual, ok := relation.holds(optionValues); !ok {
+				return &ErrRelation{Option: key, Value: value, Relation: relation, Actual: actual}
+			}
+		}
+
+		for _, relation := range option.conflictsWith {
+			if actual, ok := relation.holds(optionValues); ok {
+				return &ErrRelation{Option: key, Value: value, Relation: relation, Conflict: true, Actual: actual}
+			}
+		}
+	}
+
+	if option.valuesValidator != nil {
+		if err := option.valuesValidator.ValidateValues(value, optionValues); err != nil {
+			return errors.Wrap(ErrMalformedInput, fmt.Sprintf("%s: %s", key, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// ValidateValues validates the relations of all options that have a value in optionValues.
+// The errors of all options are joined.
+func (o *Options) ValidateValues(optionValues *OptionValues) error {
+	var errs []error
+
+	for i := range o.Base {
+		if value, ok := optionValues.Base[o.Base[i].Name()]; ok {
+			errs = append(errs, o.validateRelations(o.Base[i].Name(), &o.Base[i], value, optionValues))
+		}
+	}
+
+	for _, category := range o.Extensions {
+		for i := range category.Options {
+			option := &category.Options[i]
+			if value, ok := optionValues.Extensions[category.Name][option.Name()]; ok {
+				errs = append(errs, o.validateRelations(optionKey(category.Name, option.Name()), option, value, optionValues))
+			}
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
@@ -0,0 +1,104 @@
+package gotemplate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_ValidateValues(t *testing.T) {
+	errOdd := errors.New("must be even")
+
+	options := &Options{
+		Base: []Option{
+			NewOption("name", "", StaticValue("")),
+		},
+		Extensions: []Category{
+			{
+				Name: "grpc",
+				Options: []Option{
+					NewOption("base", "", StaticValue(false)),
+					NewOption("gateway", "", StaticValue(false), WithRequires(Relation{Option: "grpc.base"})),
+					NewOption("provider", "", StaticValue(0),
+						WithConflictsWith(Relation{Option: "grpc.gateway", Value: true}),
+						WithValuesValidator(ValuesValidatorFunc(func(value interface{}, optionValues *OptionValues) error {
+							if value.(int)%2 != 0 {
+								return errOdd
+							}
+							return nil
+						})),
+					),
+				},
+			},
+		},
+	}
+
+	values := func(base, gateway bool, provider int) *OptionValues {
+		return &OptionValues{
+			Base:       OptionNameToValue{"name": "name"},
+			Extensions: map[string]OptionNameToValue{"grpc": {"base": base, "gateway": gateway, "provider": provider}},
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		require.NoError(t, options.ValidateValues(values(true, true, 0)))
+		// relations of disabled options are not checked
+		require.NoError(t, options.ValidateValues(values(false, false, 2)))
+	})
+
+	t.Run("requirement not met", func(t *testing.T) {
+		err := options.ValidateValues(values(false, true, 0))
+
+		var errRelation *ErrRelation
+		require.ErrorAs(t, err, &errRelation)
+		assert.Equal(t, &ErrRelation{Option: "grpc.gateway", Value: true, Relation: Relation{Option: "grpc.base"}, Actual: false}, errRelation)
+		assert.EqualError(t, err, "grpc.gateway=true requires grpc.base to be enabled (it is false)")
+	})
+
+	t.Run("conflict and values validator", func(t *testing.T) {
+		err := options.ValidateValues(values(true, true, 2))
+		require.EqualError(t, err, "grpc.provider=2 conflicts with grpc.gateway being true (it is true), change one of them")
+
+		err = options.ValidateValues(values(true, false, 3))
+		require.ErrorIs(t, err, ErrMalformedInput)
+		require.ErrorContains(t, err, "grpc.provider: must be even")
+	})
+
+	t.Run("one of several values", func(t *testing.T) {
+		options := &Options{Base: []Option{
+			NewOption("license", "", StaticValue(0)),
+			NewOption("author", "", StaticValue(""), WithRequires(Relation{Option: "license", OneOf: []interface{}{1, 2}})),
+		}}
+
+		require.NoError(t, options.ValidateValues(&OptionValues{Base: OptionNameToValue{"license": 2, "author": "me"}}))
+		// the default is used for hidden options
+		require.NoError(t, options.ValidateValues(&OptionValues{Base: OptionNameToValue{"license": 3, "author": ""}}))
+		require.EqualError(t, options.ValidateValues(&OptionValues{Base: OptionNameToValue{"license": 3, "author": "me"}}),
+			"author=me requires license to be one of 1, 2 (it is 3)")
+	})
+
+	t.Run("enabled defaults are checked", func(t *testing.T) {
+		options := &Options{Base: []Option{
+			NewOption("base", "", StaticValue(true)),
+			NewOption("gateway", "", StaticValue(true), WithConflictsWith(Relation{Option: "base"})),
+		}}
+
+		require.EqualError(t, options.ValidateValues(&OptionValues{Base: OptionNameToValue{"base": true, "gateway": true}}),
+			"gateway=true conflicts with base being enabled (it is true), change one of them")
+	})
+
+	t.Run("error on unknown option", func(t *testing.T) {
+		options := &Options{Base: []Option{NewOption("a", "", StaticValue(false), WithRequires(Relation{Option: "b"}))}}
+		err := options.ValidateValues(&OptionValues{Base: OptionNameToValue{"a": false}})
+		require.ErrorIs(t, err, ErrMalformedInput)
+	})
+}
+
+func TestOption_ShouldDisplay_requires(t *testing.T) {
+	option := NewOption("gateway", "", StaticValue(false), WithRequires(Relation{Option: "grpc.base"}))
+
+	assert.False(t, option.ShouldDisplay(&OptionValues{Extensions: map[string]OptionNameToValue{"grpc": {"base": false}}}))
+	assert.True(t, option.ShouldDisplay(&OptionValues{Extensions: map[string]OptionNameToValue{"grpc": {"base": true}}}))
+}
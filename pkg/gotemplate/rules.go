@@ -0,0 +1,296 @@
+package gotemplate
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// rulesFile is the file in the template root that defines how the template's files are handled.
+// It is not part of the generated project.
+const rulesFile = ".gtrules.yml"
+
+// sniffLength is the number of bytes that are checked for NUL bytes to detect binary files like git does.
+const sniffLength = 8000
+
+// defaultBinaryExtensions are the extensions of files that are always copied as is.
+var defaultBinaryExtensions = []string{ //nolint:gochecknoglobals // static list of extensions
+	".png", ".jpg", ".jpeg", ".gif", ".ico", ".webp", ".bmp",
+	".woff", ".woff2", ".ttf", ".otf", ".eot",
+	".zip", ".gz", ".tgz", ".tar", ".jar",
+	".pdf", ".wasm", ".exe", ".dll", ".so", ".dylib",
+}
+
+// RenderMode defines how the content of a template file is processed.
+type RenderMode string
+
+const (
+	// RenderTemplate executes the file as a text/template.
+	RenderTemplate RenderMode = "template"
+	// RenderRaw copies the file as is.
+	RenderRaw RenderMode = "raw"
+)
+
+// templateRules contains all rules of a template's rules file.
+type templateRules struct {
+	// Render defines how files are rendered, the first rule with a matching glob is used.
+	// Files without a matching rule are rendered as template with the default delimiters.
+	Render []renderRule `yaml:"render"`
+	// Include defines conditions for files and directories to be part of the generated project.
+	// A path is only included if the conditions of all matching rules are true,
+	// paths without a matching rule are always included.
+	Include []includeRule `yaml:"include"`
+	// Binary contains additional extensions (e.g. ".sqlite") of files that are copied as is.
+	// Files with one of the defaultBinaryExtensions or a content that is not text are always copied as is.
+	Binary []string `yaml:"binary"`
+	// Modes defines the permissions of generated files, the first rule with a matching glob is used.
+	// Files without a matching rule are executable if the template file is executable or starts with a shebang.
+	Modes []modeRule `yaml:"modes"`
+}
+
+type modeRule struct {
+	// Glob is matched like renderRule.Glob.
+	Glob string `yaml:"glob"`
+	// Mode are the octal permissions of the file, e.g. "0755".
+	Mode string `yaml:"mode"`
+}
+
+type includeRule struct {
+	// Glob is matched like renderRule.Glob.
+	// If a directory is excluded its whole content is excluded as well.
+	Glob string `yaml:"glob"`
+	// If is a template pipeline without delimiters that is evaluated against the OptionValues,
+	// e.g. ".Extensions.grpc.base" or "eq .Extensions.ci.provider 1".
+	If string `yaml:"if"`
+}
+
+type renderRule struct {
+	// Glob is matched against the file's path relative to the template root (before rendering the path).
+	// Besides the syntax of path.Match "**" matches any number of directories.
+	Glob string     `yaml:"glob"`
+	Mode RenderMode `yaml:"mode"`
+	// Delims are the left and right delimiters used to render the file, e.g. ["[[", "]]"].
+	// This comes in handy for files containing "{{" themselves like GitHub workflows.
+	Delims []string `yaml:"delims"`
+}
+
+// loadTemplateRules loads the rules file from the root of fsys.
+// A template without a rules file has no rules.
+func loadTemplateRules(fsys fs.FS, root string) (*templateRules, error) {
+	rulesBytes, err := fs.ReadFile(fsys, path.Join(root, rulesFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &templateRules{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rules templateRules
+	if err := yaml.Unmarshal(rulesBytes, &rules); err != nil {
+		return nil, errors.Wrap(err, rulesFile)
+	}
+
+	if err := rules.validate(); err != nil {
+		return nil, errors.Wrap(err, rulesFile)
+	}
+
+	return &rules, nil
+}
+
+func (r *templateRules) validate() error {
+	for _, rule := range r.Include {
+		if err := validateGlob(rule.Glob); err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(rule.If) == "" {
+			return errors.Wrapf(ErrMalformedInput, "glob %q: include rules need a condition", rule.Glob)
+		}
+	}
+
+	for _, rule := range r.Render {
+		if err := validateGlob(rule.Glob); err != nil {
+			return err
+		}
+
+		switch rule.Mode {
+		case RenderTemplate:
+		case RenderRaw:
+			if len(rule.Delims) > 0 {
+				return errors.Wrapf(ErrMalformedInput, "glob %q: delims are not supported for mode %q", rule.Glob, rule.Mode)
+			}
+		default:
+			return errors.Wrapf(ErrMalformedInput, "glob %q: unknown mode %q", rule.Glob, rule.Mode)
+		}
+
+		if len(rule.Delims) != 0 && len(rule.Delims) != 2 {
+			return errors.Wrapf(ErrMalformedInput, "glob %q: delims need to contain exactly a left and a right delimiter", rule.Glob)
+		}
+	}
+
+	for _, extension := range r.Binary {
+		if !strings.HasPrefix(extension, ".") {
+			return errors.Wrapf(ErrMalformedInput, "binary extension %q needs to start with a dot", extension)
+		}
+	}
+
+	for _, rule := range r.Modes {
+		if err := validateGlob(rule.Glob); err != nil {
+			return err
+		}
+
+		if _, err := rule.perm(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateGlob(glob string) error {
+	if _, err := path.Match(strings.ReplaceAll(glob, "**", "*"), ""); err != nil {
+		return errors.Wrapf(ErrMalformedInput, "glob %q: %s", glob, err)
+	}
+
+	return nil
+}
+
+// includeRules returns all include rules matching filePath (relative to the template root).
+func (r *templateRules) includeRules(filePath string) []includeRule {
+	var matching []includeRule
+
+	for _, rule := range r.Include {
+		if matchGlob(rule.Glob, filePath) {
+			matching = append(matching, rule)
+		}
+	}
+
+	return matching
+}
+
+// isIncluded evaluates the conditions of all include rules matching filePath.
+func (gt *GT) isIncluded(rules *templateRules, filePath string, optionValues *OptionValues) (bool, error) {
+	for _, rule := range rules.includeRules(filePath) {
+		result, err := gt.executeTemplateString(rulesFile, fmt.Sprintf("{{ if %s }}true{{ end }}", rule.If), optionValues)
+		if err != nil {
+			return false, errors.Wrapf(err, "evaluating include condition %q of %q", rule.If, rule.Glob)
+		}
+
+		if result != "true" {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// renderRule returns the rule for the file at filePath (relative to the template root).
+func (r *templateRules) renderRule(filePath string) renderRule {
+	for _, rule := range r.Render {
+		if matchGlob(rule.Glob, filePath) {
+			return rule
+		}
+	}
+
+	return renderRule{Mode: RenderTemplate}
+}
+
+// delims returns the left and right delimiters of the rule.
+// Empty strings are returned for the default delimiters.
+func (r renderRule) delims() (string, string) {
+	if len(r.Delims) != 2 { //nolint:gomnd // left and right delimiter
+		return "", ""
+	}
+
+	return r.Delims[0], r.Delims[1]
+}
+
+// isBinary reports whether the file at filePath (relative to the template root) is copied as is.
+// That is the case for files with one of the binary extensions or if content contains NUL bytes
+// or is no valid UTF-8, since text/template would corrupt it.
+func (r *templateRules) isBinary(filePath string, content []byte) bool {
+	extension := strings.ToLower(path.Ext(filePath))
+	for _, extensions := range [][]string{defaultBinaryExtensions, r.Binary} {
+		for _, binaryExtension := range extensions {
+			if extension == strings.ToLower(binaryExtension) {
+				return true
+			}
+		}
+	}
+
+	sniff := content
+	if len(sniff) > sniffLength {
+		sniff = sniff[:sniffLength]
+	}
+
+	return bytes.IndexByte(sniff, 0) >= 0 || !utf8.Valid(content)
+}
+
+// fileMode returns the permissions of the file at filePath (relative to the template root).
+// An explicit mode rule wins over the permissions of the template file and the content.
+// The returned bool reports whether the mode has been declared explicitly.
+func (r *templateRules) fileMode(filePath string, templateMode fs.FileMode, content []byte) (fs.FileMode, bool) {
+	for _, rule := range r.Modes {
+		if matchGlob(rule.Glob, filePath) {
+			// the error can be ignored since the rules have been validated
+			perm, _ := rule.perm()
+			return perm, true
+		}
+	}
+
+	// files that are executable in the template or contain a shebang should be executable
+	if templateMode.Perm()&0o111 != 0 || bytes.HasPrefix(bytes.TrimSpace(content), []byte("#!")) {
+		return permissionRWX, false
+	}
+
+	return permissionRW, false
+}
+
+func (r modeRule) perm() (fs.FileMode, error) {
+	perm, err := strconv.ParseUint(r.Mode, 8, 32)
+	if err != nil || perm > uint64(fs.ModePerm) {
+		return 0, errors.Wrapf(ErrMalformedInput, "glob %q: mode %q is no octal file permission like \"0755\"", r.Glob, r.Mode)
+	}
+
+	return fs.FileMode(perm), nil
+}
+
+// matchGlob reports whether name matches the slash separated pattern.
+// Besides the syntax supported by path.Match a "**" element matches zero or more directories.
+func matchGlob(pattern, name string) bool {
+	return matchGlobParts(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobParts(patternParts, nameParts []string) bool {
+	if len(patternParts) == 0 {
+		return len(nameParts) == 0
+	}
+
+	if patternParts[0] == "**" {
+		for i := 0; i <= len(nameParts); i++ {
+			if matchGlobParts(patternParts[1:], nameParts[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(nameParts) == 0 {
+		return false
+	}
+
+	matched, err := path.Match(patternParts[0], nameParts[0])
+	if err != nil || !matched {
+		return false
+	}
+
+	return matchGlobParts(patternParts[1:], nameParts[1:])
+}
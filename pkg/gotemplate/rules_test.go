@@ -0,0 +1,181 @@
+package gotemplate
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gotemplate "github.com/schwarzit/go-template"
+	ownexec "github.com/schwarzit/go-template/pkg/exec"
+)
+
+func Test_matchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		matches bool
+	}{
+		{pattern: "*.go", name: "main.go", matches: true},
+		{pattern: "*.go", name: "cmd/main.go", matches: false},
+		{pattern: "**/*.go", name: "main.go", matches: true},
+		{pattern: "**/*.go", name: "internal/log/logger.go", matches: true},
+		{pattern: "internal/**", name: "internal/log/logger.go", matches: true},
+		{pattern: "internal/**/*_test.go", name: "internal/log/logger.go", matches: false},
+		{pattern: ".github/workflows/*.yml", name: ".github/workflows/main.yml", matches: true},
+		{pattern: ".github/workflows/*.yml", name: ".gitlab-ci.yml", matches: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.name, func(t *testing.T) {
+			assert.Equal(t, test.matches, matchGlob(test.pattern, test.name))
+		})
+	}
+}
+
+func Test_loadTemplateRules(t *testing.T) {
+	t.Run("no rules if file does not exist", func(t *testing.T) {
+		rules, err := loadTemplateRules(fstest.MapFS{}, "root")
+		require.NoError(t, err)
+		require.Equal(t, renderRule{Mode: RenderTemplate}, rules.renderRule("any.go"))
+	})
+
+	t.Run("first matching rule is used", func(t *testing.T) {
+		rules, err := loadTemplateRules(fstest.MapFS{
+			"root/" + rulesFile: {Data: []byte(`
+render:
+  - glob: "raw/**"
+    mode: raw
+  - glob: "**/*.yml"
+    mode: template
+    delims: ["[[", "]]"]
+`)},
+		}, "root")
+		require.NoError(t, err)
+
+		require.Equal(t, RenderRaw, rules.renderRule("raw/some.yml").Mode)
+
+		left, right := rules.renderRule("other/some.yml").delims()
+		require.Equal(t, "[[", left)
+		require.Equal(t, "]]", right)
+
+		left, right = rules.renderRule("main.go").delims()
+		require.Empty(t, left)
+		require.Empty(t, right)
+	})
+
+	t.Run("error on invalid rules", func(t *testing.T) {
+		invalidRules := []string{
+			`render: [{glob: "*.go", mode: unknown}]`,
+			`render: [{glob: "*.go", mode: raw, delims: ["[[", "]]"]}]`,
+			`render: [{glob: "*.go", mode: template, delims: ["[["]}]`,
+			`render: [{glob: "[", mode: raw}]`,
+			`binary: [png]`,
+			`modes: [{glob: "*.sh", mode: "rwx"}]`,
+			`modes: [{glob: "*.sh", mode: "1777"}]`,
+		}
+
+		for _, invalid := range invalidRules {
+			_, err := loadTemplateRules(fstest.MapFS{
+				"root/" + rulesFile: {Data: []byte(invalid)},
+			}, "root")
+			require.ErrorIs(t, err, ErrMalformedInput, invalid)
+		}
+	})
+}
+
+// Test_templateRules_Include makes sure that every file of the template is either covered by an include rule
+// and only generated if its conditions are met, or is included unconditionally in every test project.
+func Test_templateRules_Include(t *testing.T) {
+	rules, err := loadTemplateRules(gotemplate.FS, gotemplate.Key)
+	require.NoError(t, err)
+
+	var templatePaths []string
+	err = fs.WalkDir(gotemplate.FS, gotemplate.Key, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		relPath := strings.TrimPrefix(path, gotemplate.Key+"/")
+		if relPath != rulesFile {
+			templatePaths = append(templatePaths, relPath)
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	t.Run("every include rule matches template files", func(t *testing.T) {
+		for _, rule := range rules.Include {
+			matched := false
+			for _, templatePath := range templatePaths {
+				for _, p := range pathWithParents(templatePath) {
+					matched = matched || matchGlob(rule.Glob, p)
+				}
+			}
+
+			assert.True(t, matched, "rule %q does not match any file", rule.Glob)
+		}
+	})
+
+	valuesFiles, err := filepath.Glob("../../test_project_values/*.yml")
+	require.NoError(t, err)
+	valuesFiles = append(valuesFiles, "testdata/values.yml")
+
+	for _, valuesFile := range valuesFiles {
+		t.Run(valuesFile, func(t *testing.T) {
+			gt := New()
+			gt.Out = &bytes.Buffer{}
+			gt.Err = &bytes.Buffer{}
+			gt.CmdRunner = ownexec.CmdRunnerFunc(func(cmd *exec.Cmd) (string, error) {
+				return "go version go1.21.3 linux/amd64", nil
+			})
+
+			optionValues, err := gt.LoadConfigValuesFromFile(valuesFile)
+			require.NoError(t, err)
+
+			tmpDir := t.TempDir()
+			err = gt.InitNewProject(context.Background(), &NewRepositoryOptions{OutputDir: tmpDir, OptionValues: optionValues})
+			require.NoError(t, err)
+
+			targetDir := filepath.Join(tmpDir, optionValues.Base["projectSlug"].(string))
+			for _, templatePath := range templatePaths {
+				expectIncluded := true
+				for _, p := range pathWithParents(templatePath) {
+					included, err := gt.isIncluded(rules, p, optionValues)
+					require.NoError(t, err)
+					expectIncluded = expectIncluded && included
+				}
+
+				renderedPath, err := gt.executeTemplateString(templatePath, templatePath, optionValues)
+				require.NoError(t, err)
+
+				_, err = os.Stat(filepath.Join(targetDir, renderedPath))
+				if expectIncluded {
+					assert.NoError(t, err, "%s should be generated", templatePath)
+				} else {
+					assert.ErrorIs(t, err, os.ErrNotExist, "%s should not be generated", templatePath)
+				}
+			}
+		})
+	}
+}
+
+// pathWithParents returns p and all of its parent directories.
+func pathWithParents(p string) []string {
+	paths := []string{p}
+	for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+		paths = append(paths, dir)
+	}
+
+	return paths
+}
@@ -1,12 +1,25 @@
 package gotemplate
 
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultValueTimeout bounds the computation of ContextValues without a Timeout.
+const defaultValueTimeout = 2 * time.Second
+
 var (
 	_ Valuer       = &Value{}
 	_ Valuer       = DynamicValue(nil)
+	_ Valuer       = &ContextValue{}
 	_ BoolValuer   = BoolValue(false)
 	_ BoolValuer   = DynamicBoolValue(nil)
 	_ StringValuer = StringValue("")
 	_ StringValuer = DynamicStringValue(nil)
+	_ StringValuer = FallbackStringValue{}
 )
 
 type Valuer interface {
@@ -31,6 +44,91 @@ func (f DynamicValue) Value(vals *OptionValues) interface{} {
 	return f(vals)
 }
 
+// ContextValueFunc computes a value that might need to run commands or access the network or filesystem.
+type ContextValueFunc func(ctx context.Context, vals *OptionValues) (interface{}, error)
+
+// ContextValue is a Valuer whose computation is bounded by a deadline and can fail.
+// If it fails or doesn't finish in time Fallback is used instead.
+// It has to be used as pointer since results of Independent values are kept.
+type ContextValue struct {
+	Compute  ContextValueFunc
+	Fallback interface{}
+	// Timeout bounds the computation, defaultValueTimeout is used if it is zero.
+	Timeout time.Duration
+	// Independent values don't depend on other values (Compute gets nil values).
+	// They are computed at most once and can be precomputed concurrently with Prefetch.
+	Independent bool
+
+	once   sync.Once
+	result interface{}
+	err    error
+}
+
+// Value returns the computed value or Fallback if the computation failed.
+func (v *ContextValue) Value(vals *OptionValues) interface{} {
+	value, _ := v.ValueContext(context.Background(), vals)
+	return value
+}
+
+// ValueContext returns the computed value. If the computation failed or ctx is done before it finished
+// Fallback is returned together with the reason.
+func (v *ContextValue) ValueContext(ctx context.Context, vals *OptionValues) (interface{}, error) {
+	if !v.Independent {
+		return v.compute(ctx, vals)
+	}
+
+	v.once.Do(func() {
+		v.result, v.err = v.compute(ctx, nil)
+	})
+
+	return v.result, v.err
+}
+
+// Prefetch starts computing Independent values in the background, so they are ready once they are needed.
+func (v *ContextValue) Prefetch(ctx context.Context) {
+	if v.Independent {
+		go v.ValueContext(ctx, nil) //nolint:errcheck // the result is kept for later calls
+	}
+}
+
+func (v *ContextValue) compute(ctx context.Context, vals *OptionValues) (interface{}, error) {
+	timeout := v.Timeout
+	if timeout == 0 {
+		timeout = defaultValueTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		value interface{}
+		err   error
+	}
+
+	// the channel is buffered so computations that ignore ctx don't block forever after the deadline
+	done := make(chan result, 1)
+
+	go func() {
+		value, err := v.Compute(ctx, vals)
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return v.Fallback, ctx.Err()
+	case r := <-done:
+		if r.err != nil {
+			return v.Fallback, r.err
+		}
+
+		if r.value == nil {
+			return v.Fallback, errors.New("no value computed")
+		}
+
+		return r.value, nil
+	}
+}
+
 type BoolValuer interface {
 	Value(vals *OptionValues) bool
 }
@@ -62,3 +160,29 @@ type DynamicStringValue func(vals *OptionValues) string
 func (f DynamicStringValue) Value(vals *OptionValues) string {
 	return f(vals)
 }
+
+// FallbackStringValue is a DynamicStringValue with a static fallback,
+// which is used if no values are available, e.g. when generating the docs.
+type FallbackStringValue struct {
+	Fallback string
+	Dynamic  DynamicStringValue
+}
+
+func (v FallbackStringValue) Value(vals *OptionValues) string {
+	if vals == nil || v.Dynamic == nil {
+		return v.Fallback
+	}
+
+	return v.Dynamic(vals)
+}
+
+// staticString returns the value of valuer that doesn't depend on any input.
+// It is empty for DynamicStringValues since they can't be evaluated without input.
+func staticString(valuer StringValuer) string {
+	switch v := valuer.(type) {
+	case StringValue, FallbackStringValue:
+		return v.Value(nil)
+	default:
+		return ""
+	}
+}
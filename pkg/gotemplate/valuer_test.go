@@ -0,0 +1,91 @@
+package gotemplate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextValue(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	t.Run("computed value", func(t *testing.T) {
+		value := &ContextValue{
+			Compute: func(ctx context.Context, vals *OptionValues) (interface{}, error) {
+				return vals.Base["name"].(string) + "-slug", nil
+			},
+			Fallback: "fallback",
+		}
+
+		assert.Equal(t, "name-slug", value.Value(&OptionValues{Base: OptionNameToValue{"name": "name"}}))
+	})
+
+	t.Run("fallback on failure", func(t *testing.T) {
+		value := &ContextValue{
+			Compute:  func(ctx context.Context, vals *OptionValues) (interface{}, error) { return nil, errFailed },
+			Fallback: "fallback",
+		}
+
+		result, err := value.ValueContext(context.Background(), nil)
+		require.ErrorIs(t, err, errFailed)
+		assert.Equal(t, "fallback", result)
+	})
+
+	t.Run("fallback on timeout", func(t *testing.T) {
+		value := &ContextValue{
+			// ignores ctx on purpose
+			Compute: func(ctx context.Context, vals *OptionValues) (interface{}, error) {
+				time.Sleep(time.Second)
+				return "value", nil
+			},
+			Fallback: "fallback",
+			Timeout:  time.Millisecond,
+		}
+
+		result, err := value.ValueContext(context.Background(), nil)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, "fallback", result)
+	})
+
+	t.Run("independent values are computed once", func(t *testing.T) {
+		var calls int32
+
+		value := &ContextValue{
+			Compute: func(ctx context.Context, vals *OptionValues) (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				return "value", nil
+			},
+			Independent: true,
+		}
+
+		value.Prefetch(context.Background())
+		assert.Equal(t, "value", value.Value(nil))
+		assert.Equal(t, "value", value.Value(NewOptionValues()))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestGT_defaultValue(t *testing.T) {
+	option := NewOption("author", "", &ContextValue{
+		Compute: func(ctx context.Context, vals *OptionValues) (interface{}, error) {
+			return nil, errors.New("git config user.name is empty")
+		},
+		Fallback: "Marty Mc Fly",
+	})
+
+	out := &bytes.Buffer{}
+	gt := &GT{Streams: Streams{Out: out}}
+
+	assert.Equal(t, "Marty Mc Fly", gt.defaultValue(context.Background(), "openSource.author", &option, NewOptionValues()))
+	assert.Empty(t, out.String())
+
+	gt.Verbose = true
+	assert.Equal(t, "Marty Mc Fly", gt.defaultValue(context.Background(), "openSource.author", &option, NewOptionValues()))
+	assert.Equal(t, "Using Marty Mc Fly as default of openSource.author: git config user.name is empty\n", out.String())
+}
@@ -1,6 +1,11 @@
 package gotemplate
 
 import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
 	"github.com/schwarzit/go-template/config"
 	"github.com/schwarzit/go-template/pkg/repos"
 )
@@ -8,6 +13,11 @@ import (
 const (
 	goTemplateGithubOwner = "schwarzit"
 	goTemplateGithubRepo  = "go-template"
+	goTemplateModuleOwner = "github.com/" + goTemplateGithubOwner
+	// releaseRequestTimeout bounds the requests to the release source, the version check must not slow down gt.
+	releaseRequestTimeout = time.Second
+	// releaseCheckTimeout bounds the whole version check, which might need several requests for all pages of tags.
+	releaseCheckTimeout = 3 * time.Second
 )
 
 func (gt *GT) PrintVersion() {
@@ -15,13 +25,75 @@ func (gt *GT) PrintVersion() {
 }
 
 func (gt *GT) CheckVersion() {
-	tag, err := repos.LatestGithubReleaseTag(gt.GithubTagLister, goTemplateGithubOwner, goTemplateGithubRepo)
+	lister, source, err := gt.releaseSource()
+	if err != nil {
+		gt.printWarningf("unable to fetch version information: %s", err)
+		return
+	}
+
+	opts, err := source.ReleaseOptions()
+	if err != nil {
+		gt.printWarningf("unable to fetch version information: %s", err)
+		return
+	}
+
+	// users of pre-releases are told about newer pre-releases as well
+	if source.Prereleases == "" && config.VersionSemver.Prerelease() != "" {
+		opts.Prereleases = repos.PrereleasesInclude
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), releaseCheckTimeout)
+	defer cancel()
+
+	release, err := repos.LatestRelease(ctx, lister, source.Owner, source.Repo, opts)
 	if err != nil {
 		gt.printWarningf("unable to fetch version information. There could be newer release for go/template.")
 		return
 	}
 
-	if tag.GreaterThan(config.VersionSemver) {
-		gt.printWarningf("newer version available: %s. Pls make sure to stay up to date to enjoy the latest features.", tag)
+	if len(release.Skipped) > 0 && gt.Verbose {
+		gt.printf("Ignored tags that are no semantic versions: %s\n", strings.Join(release.Skipped, ", "))
+	}
+
+	if release.Version.GreaterThan(config.VersionSemver) {
+		gt.printWarningf("newer version available: %s. Pls make sure to stay up to date to enjoy the latest features.", release.Tag)
 	}
 }
+
+// releaseSource returns the TagLister and the config of the source gt's releases are looked up at.
+// The source configured in the last of gt.ConfigFiles that configures one is used, GitHub by default.
+// gt.TagLister (or the deprecated gt.GithubTagLister) replaces the configured source's TagLister if it is set.
+func (gt *GT) releaseSource() (repos.TagLister, repos.SourceConfig, error) {
+	source := repos.SourceConfig{Type: repos.SourceGithub}
+
+	for _, file := range gt.ConfigFiles {
+		config, err := LoadConfig(file)
+		if err != nil {
+			return nil, source, err
+		}
+
+		if config.Releases != nil {
+			source = *config.Releases
+		}
+	}
+
+	if source.Owner == "" && source.Repo == "" {
+		source.Owner, source.Repo = goTemplateGithubOwner, goTemplateGithubRepo
+		// module proxies need the module path without its last element as owner
+		if source.Type == repos.SourceGoProxy {
+			source.Owner = goTemplateModuleOwner
+		}
+	}
+
+	if gt.TagLister != nil {
+		return gt.TagLister, source, nil
+	}
+
+	if gt.GithubTagLister != nil {
+		return gt.GithubTagLister, source, nil
+	}
+
+	lister, err := repos.NewTagLister(source, &http.Client{Timeout: releaseRequestTimeout})
+
+	return lister, source, err
+}
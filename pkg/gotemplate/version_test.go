@@ -3,6 +3,11 @@ package gotemplate_test
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -11,6 +16,7 @@ import (
 	"github.com/schwarzit/go-template/pkg/gotemplate"
 	"github.com/schwarzit/go-template/pkg/repos"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGT_PrintVersion(t *testing.T) {
@@ -25,7 +31,7 @@ func TestGT_PrintVersion(t *testing.T) {
 func TestGT_CheckVersion(t *testing.T) {
 	tests := []struct {
 		name          string
-		listerFunc    repos.GithubTagListerFunc
+		listerFunc    repos.TagListerFunc
 		expectWarning bool
 	}{
 		{
@@ -59,7 +65,7 @@ func TestGT_CheckVersion(t *testing.T) {
 					Out: out,
 					Err: out,
 				},
-				GithubTagLister: test.listerFunc,
+				TagLister: test.listerFunc,
 			}
 
 			gt.CheckVersion()
@@ -71,3 +77,61 @@ func TestGT_CheckVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestGT_CheckVersion_configuredSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/repos/tools/gt/tags", r.URL.Path)
+		_, _ = fmt.Fprint(w, `[{"name": "v999.0.0"}]`)
+	}))
+	defer server.Close()
+
+	configFile := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configFile, []byte(fmt.Sprintf(`releases:
+  type: gitea
+  url: %s
+  owner: tools
+  repo: gt
+`, server.URL)), 0o600))
+
+	out := &bytes.Buffer{}
+	gt := gotemplate.GT{Streams: gotemplate.Streams{Out: out, Err: out}, ConfigFiles: []string{configFile}}
+
+	gt.CheckVersion()
+	assert.Contains(t, out.String(), "newer version available: v999.0.0")
+}
+
+func TestGT_CheckVersion_goProxyDefaultModule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/github.com/schwarzit/go-template/@v/list" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprint(w, "v999.0.0\n")
+	}))
+	defer server.Close()
+
+	configFile := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configFile, []byte(fmt.Sprintf("releases:\n  type: goproxy\n  url: %s\n", server.URL)), 0o600))
+
+	out := &bytes.Buffer{}
+	gt := gotemplate.GT{Streams: gotemplate.Streams{Out: out, Err: out}, ConfigFiles: []string{configFile}}
+
+	gt.CheckVersion()
+	assert.Contains(t, out.String(), "newer version available: v999.0.0")
+}
+
+func TestGT_CheckVersion_deprecatedGithubTagLister(t *testing.T) {
+	// NewOptions still accepts the former GithubTagLister type.
+	var _ func(repos.GithubTagLister) *gotemplate.Options = gotemplate.NewOptions
+
+	out := &bytes.Buffer{}
+	gt := gotemplate.GT{
+		Streams: gotemplate.Streams{Out: out, Err: out},
+		GithubTagLister: repos.GithubTagListerFunc(func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"v999.0.0"}, nil
+		}),
+	}
+
+	gt.CheckVersion()
+	assert.Contains(t, out.String(), "newer version available: v999.0.0")
+}
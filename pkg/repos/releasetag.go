@@ -7,21 +7,88 @@ import (
 	"github.com/pkg/errors"
 )
 
-var ErrNoTagsAvailable = errors.New("no tags available")
+var (
+	ErrNoTagsAvailable = errors.New("no tags available")
+	ErrNoMatchingTag   = errors.New("no tag is a matching semantic version")
+)
 
-type GithubTagLister interface {
+// TagLister lists the tags of a repository at a release source like GitHub, see NewTagLister.
+type TagLister interface {
 	ListTags(ctx context.Context, owner, repo string) ([]string, error)
 }
 
-type GithubTagListerFunc func(ctx context.Context, owner, repo string) ([]string, error)
+// TagListerFunc is a function implementing the TagLister interface.
+type TagListerFunc func(ctx context.Context, owner, repo string) ([]string, error)
 
-func (f GithubTagListerFunc) ListTags(ctx context.Context, owner, repo string) ([]string, error) {
+func (f TagListerFunc) ListTags(ctx context.Context, owner, repo string) ([]string, error) {
 	return f(ctx, owner, repo)
 }
 
-// LatestGithubReleaseTag returns the latest release tag for a given repo.
-func LatestGithubReleaseTag(lister GithubTagLister, owner, repo string) (*semver.Version, error) {
-	tags, err := lister.ListTags(context.Background(), owner, repo)
+// PagedTagLister is a TagLister that requests the tags in pages, listing the newest tags first.
+// LatestRelease uses it to request only the pages it needs, e.g. to save GitHub's rate limit.
+type PagedTagLister interface {
+	TagLister
+	// ListTagsUntil lists the tags like ListTags but stops after the page containing a tag done returns true for.
+	ListTagsUntil(ctx context.Context, owner, repo string, done func(tag string) bool) ([]string, error)
+}
+
+// GithubTagLister is the former name of TagLister.
+//
+// Deprecated: Use TagLister.
+type GithubTagLister = TagLister
+
+// GithubTagListerFunc is the former name of TagListerFunc.
+//
+// Deprecated: Use TagListerFunc.
+type GithubTagListerFunc = TagListerFunc
+
+// PrereleasePolicy decides whether pre-release versions (e.g. "v1.0.0-rc.1") are considered releases.
+type PrereleasePolicy string
+
+const (
+	// PrereleasesExclude ignores pre-releases, it is the default.
+	PrereleasesExclude PrereleasePolicy = "exclude"
+	// PrereleasesInclude considers pre-releases like any other release.
+	PrereleasesInclude PrereleasePolicy = "include"
+)
+
+// ReleaseOptions restrict the tags that are considered releases.
+type ReleaseOptions struct {
+	// Constraint restricts the versions of the releases, e.g. "^1" to stay on the major version 1.
+	// If it is nil any version is a release.
+	Constraint *semver.Constraints
+	// Prereleases is PrereleasesExclude if it is empty.
+	Prereleases PrereleasePolicy
+}
+
+// Release is a tag of a repository that is a valid semantic version.
+type Release struct {
+	// Tag is the name of the tag, e.g. "v1.2.0".
+	Tag     string
+	Version *semver.Version
+	// Skipped are all tags of the repository that are no valid semantic versions.
+	Skipped []string
+}
+
+// LatestRelease returns the tag with the greatest version that matches opts.
+// Tags that are no valid semantic versions are skipped and returned in Release.Skipped.
+// If lister is a PagedTagLister no further pages are requested once a stable release matching opts is listed,
+// so Release.Skipped only contains the skipped tags of the requested pages.
+func LatestRelease(ctx context.Context, lister TagLister, owner, repo string, opts ReleaseOptions) (*Release, error) {
+	var (
+		tags []string
+		err  error
+	)
+
+	if paged, ok := lister.(PagedTagLister); ok {
+		tags, err = paged.ListTagsUntil(ctx, owner, repo, func(tag string) bool {
+			version, err := semver.NewVersion(tag)
+			return err == nil && version.Prerelease() == "" && opts.matches(version)
+		})
+	} else {
+		tags, err = lister.ListTags(ctx, owner, repo)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -30,17 +97,64 @@ func LatestGithubReleaseTag(lister GithubTagLister, owner, repo string) (*semver
 		return nil, errors.Wrap(ErrNoTagsAvailable, repo)
 	}
 
-	latest := &semver.Version{}
+	var (
+		latest  *Release
+		skipped []string
+	)
+
 	for _, tag := range tags {
-		currentVersion, err := semver.NewVersion(tag)
+		version, err := semver.NewVersion(tag)
 		if err != nil {
-			return nil, err
+			skipped = append(skipped, tag)
+			continue
+		}
+
+		if !opts.matches(version) {
+			continue
 		}
 
-		if currentVersion.GreaterThan(latest) {
-			latest = currentVersion
+		if latest == nil || version.GreaterThan(latest.Version) {
+			latest = &Release{Tag: tag, Version: version}
 		}
 	}
 
+	if latest == nil {
+		return nil, errors.Wrapf(ErrNoMatchingTag, "%s (%d tags, %d of them no semantic versions)", repo, len(tags), len(skipped))
+	}
+
+	latest.Skipped = skipped
+
 	return latest, nil
 }
+
+func (o ReleaseOptions) matches(version *semver.Version) bool {
+	if version.Prerelease() != "" && o.Prereleases != PrereleasesInclude {
+		return false
+	}
+
+	// constraints don't match pre-releases without a pre-release of their own, so the release version is checked
+	if o.Constraint != nil {
+		withoutPrerelease, _ := version.SetPrerelease("")
+		return o.Constraint.Check(&withoutPrerelease)
+	}
+
+	return true
+}
+
+// LatestReleaseTag returns the latest release tag for a given repo.
+// Pre-releases are considered, tags that are no valid semantic versions are ignored.
+func LatestReleaseTag(lister TagLister, owner, repo string) (*semver.Version, error) {
+	release, err := LatestRelease(context.Background(), lister, owner, repo, ReleaseOptions{Prereleases: PrereleasesInclude})
+	if err != nil {
+		return nil, err
+	}
+
+	return release.Version, nil
+}
+
+// LatestGithubReleaseTag returns the latest release tag for a given repo.
+//
+// Deprecated: Use LatestRelease, which works with any TagLister.
+func LatestGithubReleaseTag(lister TagLister, owner, repo string) (*semver.Version, error) {
+	return LatestReleaseTag(lister, owner, repo)
+}
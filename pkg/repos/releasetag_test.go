@@ -8,12 +8,13 @@ import (
 	"github.com/pkg/errors"
 	"github.com/schwarzit/go-template/pkg/repos"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestLatestGithubReleaseTag(t *testing.T) {
+func TestLatestReleaseTag(t *testing.T) {
 	tests := []struct {
 		name       string
-		listerFunc repos.GithubTagListerFunc
+		listerFunc repos.TagListerFunc
 		expectErr  bool
 		expectTag  *semver.Version
 	}{
@@ -38,6 +39,14 @@ func TestLatestGithubReleaseTag(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "tags that are no semver are skipped",
+			listerFunc: func(ctx context.Context, owner, repo string) ([]string, error) {
+				return []string{"v1.0.0", "latest", "v1.0.2"}, nil
+			},
+			expectErr: false,
+			expectTag: semver.MustParse("v1.0.2"),
+		},
 		{
 			name: "semver tags are returned",
 			listerFunc: func(ctx context.Context, owner, repo string) ([]string, error) {
@@ -50,7 +59,7 @@ func TestLatestGithubReleaseTag(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			tag, err := repos.LatestGithubReleaseTag(test.listerFunc, "", "")
+			tag, err := repos.LatestReleaseTag(test.listerFunc, "", "")
 			if test.expectErr {
 				assert.Error(t, err)
 			} else {
@@ -61,3 +70,52 @@ func TestLatestGithubReleaseTag(t *testing.T) {
 		})
 	}
 }
+
+func TestLatestRelease(t *testing.T) {
+	lister := repos.TagListerFunc(func(ctx context.Context, owner, repo string) ([]string, error) {
+		return []string{"v1.0.0", "nightly", "v1.1.0", "v1.2.0-rc.1", "v2.0.0", "v2.1.0-rc.1", "latest"}, nil
+	})
+
+	tests := []struct {
+		name       string
+		constraint string
+		policy     repos.PrereleasePolicy
+		expectTag  string
+	}{
+		{name: "pre-releases are excluded by default", expectTag: "v2.0.0"},
+		{name: "pre-releases included", policy: repos.PrereleasesInclude, expectTag: "v2.1.0-rc.1"},
+		{name: "same major version", constraint: "^1", expectTag: "v1.1.0"},
+		{name: "same major version with pre-releases", constraint: "^1", policy: repos.PrereleasesInclude, expectTag: "v1.2.0-rc.1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts, err := repos.SourceConfig{Constraint: test.constraint, Prereleases: test.policy}.ReleaseOptions()
+			require.NoError(t, err)
+
+			release, err := repos.LatestRelease(context.Background(), lister, "", "repo", opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectTag, release.Tag)
+			assert.Equal(t, semver.MustParse(test.expectTag), release.Version)
+			assert.Equal(t, []string{"nightly", "latest"}, release.Skipped)
+		})
+	}
+
+	t.Run("error if no tag matches", func(t *testing.T) {
+		opts, err := repos.SourceConfig{Constraint: "^3"}.ReleaseOptions()
+		require.NoError(t, err)
+
+		_, err = repos.LatestRelease(context.Background(), lister, "", "repo", opts)
+		require.ErrorIs(t, err, repos.ErrNoMatchingTag)
+		require.ErrorContains(t, err, "repo (7 tags, 2 of them no semantic versions)")
+	})
+
+	t.Run("error on invalid config", func(t *testing.T) {
+		_, err := repos.SourceConfig{Constraint: "not a constraint"}.ReleaseOptions()
+		require.ErrorIs(t, err, repos.ErrInvalidConfig)
+
+		_, err = repos.SourceConfig{Prereleases: "sometimes"}.ReleaseOptions()
+		require.ErrorIs(t, err, repos.ErrInvalidConfig)
+	})
+}
@@ -0,0 +1,345 @@
+package repos
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-github/v56/github"
+	"github.com/pkg/errors"
+)
+
+// Types of release sources, see SourceConfig.Type.
+const (
+	SourceGithub  = "github"
+	SourceGitlab  = "gitlab"
+	SourceGitea   = "gitea"
+	SourceGoProxy = "goproxy"
+)
+
+const (
+	defaultGitlabURL  = "https://gitlab.com"
+	defaultGiteaURL   = "https://gitea.com"
+	defaultGoProxyURL = "https://proxy.golang.org"
+	// tagsPerPage is the maximum number of tags requested at once.
+	tagsPerPage = 100
+)
+
+var (
+	ErrUnknownSource    = errors.New("unknown release source")
+	ErrUnexpectedStatus = errors.New("unexpected status")
+	ErrInvalidConfig    = errors.New("invalid release source config")
+)
+
+// SourceConfig configures where the tags of a repository are looked up.
+type SourceConfig struct {
+	// Type is one of "github" (default), "gitlab", "gitea" or "goproxy".
+	Type string `yaml:"type"`
+	// URL is the base URL of the source, e.g. of a GitHub Enterprise or self-hosted GitLab instance.
+	// It defaults to the public instance, for "goproxy" to the first proxy in $GOPROXY.
+	URL string `yaml:"url,omitempty"`
+	// Owner is the user, organisation or group of the repository.
+	// For "goproxy" it is the module path without its last element, e.g. "github.com/schwarzit".
+	Owner string `yaml:"owner,omitempty"`
+	// Repo is the name of the repository.
+	Repo string `yaml:"repo,omitempty"`
+	// TokenEnv is the name of the environment variable containing an access token for private repositories.
+	TokenEnv string `yaml:"tokenEnv,omitempty"`
+	// Constraint restricts the versions that are considered releases, e.g. "^1" to stay on the major version 1.
+	Constraint string `yaml:"constraint,omitempty"`
+	// Prereleases is "include" to consider pre-releases as well, by default they are excluded.
+	Prereleases PrereleasePolicy `yaml:"prereleases,omitempty"`
+}
+
+// ReleaseOptions returns the options restricting the releases as configured.
+func (c SourceConfig) ReleaseOptions() (ReleaseOptions, error) {
+	opts := ReleaseOptions{Prereleases: c.Prereleases}
+
+	switch c.Prereleases {
+	case "", PrereleasesExclude, PrereleasesInclude:
+	default:
+		return opts, errors.Wrapf(ErrInvalidConfig, "prereleases %q (available: %s, %s)", c.Prereleases, PrereleasesExclude, PrereleasesInclude)
+	}
+
+	if c.Constraint != "" {
+		constraint, err := semver.NewConstraint(c.Constraint)
+		if err != nil {
+			return opts, errors.Wrapf(ErrInvalidConfig, "constraint %q: %s", c.Constraint, err)
+		}
+
+		opts.Constraint = constraint
+	}
+
+	return opts, nil
+}
+
+// NewTagLister returns the TagLister for the source configured in config.
+// If client is nil http.DefaultClient is used.
+func NewTagLister(config SourceConfig, client *http.Client) (TagLister, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var token string
+	if config.TokenEnv != "" {
+		token = os.Getenv(config.TokenEnv)
+	}
+
+	switch config.Type {
+	case "", SourceGithub:
+		return NewGithubTagLister(client, config.URL, token)
+	case SourceGitlab:
+		return &GitlabTagLister{Client: client, URL: orDefault(config.URL, defaultGitlabURL), Token: token}, nil
+	case SourceGitea:
+		return &GiteaTagLister{Client: client, URL: orDefault(config.URL, defaultGiteaURL), Token: token}, nil
+	case SourceGoProxy:
+		return &GoProxyTagLister{Client: client, URL: orDefault(config.URL, goProxyURL())}, nil
+	default:
+		return nil, errors.Wrapf(ErrUnknownSource, "%q (available: %s, %s, %s, %s)",
+			config.Type, SourceGithub, SourceGitlab, SourceGitea, SourceGoProxy)
+	}
+}
+
+// NewGithubTagLister returns a TagLister for github.com or, if baseURL is set, a GitHub Enterprise server.
+func NewGithubTagLister(client *http.Client, baseURL, token string) (TagLister, error) {
+	githubClient := github.NewClient(client)
+	if token != "" {
+		githubClient = githubClient.WithAuthToken(token)
+	}
+
+	if baseURL != "" {
+		var err error
+		if githubClient, err = githubClient.WithEnterpriseURLs(baseURL, baseURL); err != nil {
+			return nil, err
+		}
+	}
+
+	return &githubTagLister{client: githubClient}, nil
+}
+
+// githubTagLister lists the tags of repositories on github.com or a GitHub Enterprise server.
+type githubTagLister struct {
+	client *github.Client
+}
+
+func (l *githubTagLister) ListTags(ctx context.Context, owner, repo string) ([]string, error) {
+	return l.ListTagsUntil(ctx, owner, repo, func(string) bool { return false })
+}
+
+func (l *githubTagLister) ListTagsUntil(ctx context.Context, owner, repo string, done func(tag string) bool) ([]string, error) {
+	var tagStrings []string
+
+	opts := &github.ListOptions{PerPage: tagsPerPage}
+	for {
+		tags, resp, err := l.client.Repositories.ListTags(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		isDone := false
+		for _, tag := range tags {
+			tagStrings = append(tagStrings, tag.GetName())
+			isDone = isDone || done(tag.GetName())
+		}
+
+		if isDone || resp.NextPage == 0 {
+			return tagStrings, nil
+		}
+
+		opts.Page = resp.NextPage
+	}
+}
+
+// GitlabTagLister lists the tags of projects on a GitLab instance.
+type GitlabTagLister struct {
+	Client *http.Client
+	// URL is the base URL of the instance, e.g. "https://gitlab.com".
+	URL string
+	// Token is a personal, project or group access token, it is optional for public projects.
+	Token string
+}
+
+// ListTags lists the tags of the project at "<owner>/<repo>", owner can contain subgroups.
+func (l *GitlabTagLister) ListTags(ctx context.Context, owner, repo string) ([]string, error) {
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/repository/tags?per_page=%d",
+		strings.TrimSuffix(l.URL, "/"), url.PathEscape(owner+"/"+repo), tagsPerPage)
+
+	header := http.Header{}
+	if l.Token != "" {
+		header.Set("PRIVATE-TOKEN", l.Token)
+	}
+
+	return listNamedTags(ctx, l.Client, endpoint, header)
+}
+
+// GiteaTagLister lists the tags of repositories on a Gitea (or Forgejo) instance.
+type GiteaTagLister struct {
+	Client *http.Client
+	// URL is the base URL of the instance, e.g. "https://gitea.com".
+	URL string
+	// Token is an access token, it is optional for public repositories.
+	Token string
+}
+
+func (l *GiteaTagLister) ListTags(ctx context.Context, owner, repo string) ([]string, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/repos/%s/%s/tags?limit=%d",
+		strings.TrimSuffix(l.URL, "/"), url.PathEscape(owner), url.PathEscape(repo), tagsPerPage)
+
+	header := http.Header{}
+	if l.Token != "" {
+		header.Set("Authorization", "token "+l.Token)
+	}
+
+	return listNamedTags(ctx, l.Client, endpoint, header)
+}
+
+// GoProxyTagLister lists the versions of Go modules with the GOPROXY protocol.
+// Only tags that are valid module versions are known to the proxy.
+type GoProxyTagLister struct {
+	Client *http.Client
+	// URL is the base URL of the proxy, e.g. "https://proxy.golang.org".
+	URL string
+}
+
+// ListTags lists the versions of the module "<owner>/<repo>".
+func (l *GoProxyTagLister) ListTags(ctx context.Context, owner, repo string) ([]string, error) {
+	endpoint := fmt.Sprintf("%s/%s/@v/list", strings.TrimSuffix(l.URL, "/"), escapeModulePath(owner+"/"+repo))
+
+	resp, err := get(ctx, l.Client, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var versions []string
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if version := strings.TrimSpace(scanner.Text()); version != "" {
+			versions = append(versions, version)
+		}
+	}
+
+	return versions, scanner.Err()
+}
+
+// listNamedTags lists the tags returned by APIs responding with objects containing the tag's name, like GitLab and Gitea.
+// All pages are requested by following the "next" links of the responses.
+func listNamedTags(ctx context.Context, client *http.Client, endpoint string, header http.Header) ([]string, error) {
+	var names []string
+
+	for endpoint != "" {
+		resp, err := get(ctx, client, endpoint, header)
+		if err != nil {
+			return nil, err
+		}
+
+		var tags []struct {
+			Name string `json:"name"`
+		}
+
+		err = json.NewDecoder(resp.Body).Decode(&tags)
+		resp.Body.Close()
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding tags of %s", endpoint)
+		}
+
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+
+		if endpoint, err = nextPage(resp); err != nil {
+			return nil, err
+		}
+	}
+
+	return names, nil
+}
+
+// nextPage returns the URL of the "next" link in the Link header of resp, empty if there is none.
+func nextPage(resp *http.Response) (string, error) {
+	for _, link := range strings.Split(resp.Header.Get("Link"), ",") {
+		target, params, ok := strings.Cut(link, ";")
+		if !ok || !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+			continue
+		}
+
+		next, err := resp.Request.URL.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			return "", errors.Wrap(err, "parsing link to next page")
+		}
+
+		return next.String(), nil
+	}
+
+	return "", nil
+}
+
+// get sends a GET request and returns the response if it succeeded, its body has to be closed.
+func get(ctx context.Context, client *http.Client, endpoint string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Wrapf(ErrUnexpectedStatus, "GET %s: %s", endpoint, resp.Status)
+	}
+
+	return resp, nil
+}
+
+// goProxyURL returns the first proxy in $GOPROXY or the default proxy.
+// Entries like "direct" and "off" are skipped.
+func goProxyURL() string {
+	for _, proxy := range strings.FieldsFunc(os.Getenv("GOPROXY"), func(r rune) bool { return r == ',' || r == '|' }) {
+		if strings.HasPrefix(proxy, "https://") || strings.HasPrefix(proxy, "http://") {
+			return proxy
+		}
+	}
+
+	return defaultGoProxyURL
+}
+
+// escapeModulePath escapes upper case letters of a module path like the GOPROXY protocol requires,
+// e.g. "github.com/SchwarzIT/go-template" to "github.com/!schwarz!i!t/go-template".
+func escapeModulePath(path string) string {
+	var escaped strings.Builder
+
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			escaped.WriteRune('!')
+			r = unicode.ToLower(r)
+		}
+
+		escaped.WriteRune(r)
+	}
+
+	return escaped.String()
+}
+
+func orDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
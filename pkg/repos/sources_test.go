@@ -0,0 +1,210 @@
+package repos_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/schwarzit/go-template/pkg/repos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer returns a server responding with body to requests of path (including the query),
+// after checking that the header named tokenHeader is set to token.
+func newServer(t *testing.T, path, tokenHeader, token, body string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RequestURI() != path {
+			http.NotFound(w, r)
+			return
+		}
+
+		if tokenHeader != "" && r.Header.Get(tokenHeader) != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestNewTagLister(t *testing.T) {
+	t.Setenv("RELEASES_TOKEN", "secret")
+
+	tests := []struct {
+		name   string
+		config func(url string) repos.SourceConfig
+		server func(t *testing.T) *httptest.Server
+		owner  string
+	}{
+		{
+			name: "github enterprise",
+			config: func(url string) repos.SourceConfig {
+				return repos.SourceConfig{Type: repos.SourceGithub, URL: url, TokenEnv: "RELEASES_TOKEN"}
+			},
+			server: func(t *testing.T) *httptest.Server {
+				return newServer(t, "/api/v3/repos/owner/repo/tags?per_page=100", "Authorization", "Bearer secret",
+					`[{"name": "v1.0.0"}, {"name": "v1.1.0"}]`)
+			},
+			owner: "owner",
+		},
+		{
+			name: "gitlab",
+			config: func(url string) repos.SourceConfig {
+				return repos.SourceConfig{Type: repos.SourceGitlab, URL: url, TokenEnv: "RELEASES_TOKEN"}
+			},
+			server: func(t *testing.T) *httptest.Server {
+				return newServer(t, "/api/v4/projects/group%2Fsubgroup%2Frepo/repository/tags?per_page=100", "PRIVATE-TOKEN", "secret",
+					`[{"name": "v1.0.0", "message": ""}, {"name": "v1.1.0"}]`)
+			},
+			owner: "group/subgroup",
+		},
+		{
+			name: "gitea",
+			config: func(url string) repos.SourceConfig {
+				return repos.SourceConfig{Type: repos.SourceGitea, URL: url, TokenEnv: "RELEASES_TOKEN"}
+			},
+			server: func(t *testing.T) *httptest.Server {
+				return newServer(t, "/api/v1/repos/owner/repo/tags?limit=100", "Authorization", "token secret",
+					`[{"name": "v1.0.0"}, {"name": "v1.1.0"}]`)
+			},
+			owner: "owner",
+		},
+		{
+			name: "goproxy",
+			config: func(url string) repos.SourceConfig {
+				return repos.SourceConfig{Type: repos.SourceGoProxy, URL: url}
+			},
+			server: func(t *testing.T) *httptest.Server {
+				return newServer(t, "/github.com/!schwarz!i!t/repo/@v/list", "", "", "v1.0.0\nv1.1.0\n")
+			},
+			owner: "github.com/SchwarzIT",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := test.server(t)
+
+			lister, err := repos.NewTagLister(test.config(server.URL), server.Client())
+			require.NoError(t, err)
+
+			tags, err := lister.ListTags(context.Background(), test.owner, "repo")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"v1.0.0", "v1.1.0"}, tags)
+		})
+	}
+
+	t.Run("all pages", func(t *testing.T) {
+		pages := map[string]struct{ link, body string }{
+			"1": {link: `<%s/api/v1/repos/owner/repo/tags?limit=100&page=2>; rel="next", <%[1]s/api/v1/repos/owner/repo/tags?limit=100&page=2>; rel="last"`, body: `[{"name": "v1.0.0"}]`},
+			"2": {body: `[{"name": "v1.1.0"}]`},
+		}
+
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page := r.URL.Query().Get("page")
+			if page == "" {
+				page = "1"
+			}
+
+			if pages[page].link != "" {
+				w.Header().Set("Link", fmt.Sprintf(pages[page].link, server.URL))
+			}
+
+			_, _ = fmt.Fprint(w, pages[page].body)
+		}))
+		defer server.Close()
+
+		for _, source := range []string{repos.SourceGithub, repos.SourceGitea} {
+			lister, err := repos.NewTagLister(repos.SourceConfig{Type: source, URL: server.URL}, server.Client())
+			require.NoError(t, err)
+
+			tags, err := lister.ListTags(context.Background(), "owner", "repo")
+			require.NoError(t, err, source)
+			assert.Equal(t, []string{"v1.0.0", "v1.1.0"}, tags, source)
+		}
+	})
+
+	t.Run("goproxy from environment", func(t *testing.T) {
+		server := newServer(t, "/example.com/owner/repo/@v/list", "", "", "v1.0.0\n")
+		t.Setenv("GOPROXY", "off,"+server.URL+",direct")
+
+		lister, err := repos.NewTagLister(repos.SourceConfig{Type: repos.SourceGoProxy}, server.Client())
+		require.NoError(t, err)
+
+		tags, err := lister.ListTags(context.Background(), "example.com/owner", "repo")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v1.0.0"}, tags)
+	})
+
+	t.Run("error on unexpected status", func(t *testing.T) {
+		server := newServer(t, "/other", "", "", "")
+
+		lister, err := repos.NewTagLister(repos.SourceConfig{Type: repos.SourceGitea, URL: server.URL}, server.Client())
+		require.NoError(t, err)
+
+		_, err = lister.ListTags(context.Background(), "owner", "repo")
+		require.ErrorIs(t, err, repos.ErrUnexpectedStatus)
+	})
+
+	t.Run("error on unknown source", func(t *testing.T) {
+		_, err := repos.NewTagLister(repos.SourceConfig{Type: "bitbucket"}, nil)
+		require.ErrorIs(t, err, repos.ErrUnknownSource)
+	})
+}
+
+func TestLatestRelease_githubPages(t *testing.T) {
+	pages := map[string]struct{ link, body string }{
+		"":  {link: `<%s/api/v3/repos/owner/repo/tags?per_page=100&page=2>; rel="next"`, body: `[{"name": "v2.0.0-rc.1"}, {"name": "v1.1.0"}]`},
+		"2": {body: `[{"name": "v1.0.0"}]`},
+	}
+
+	var (
+		server    *httptest.Server
+		requested []string
+	)
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		requested = append(requested, page)
+
+		if pages[page].link != "" {
+			w.Header().Set("Link", fmt.Sprintf(pages[page].link, server.URL))
+		}
+
+		_, _ = fmt.Fprint(w, pages[page].body)
+	}))
+	defer server.Close()
+
+	lister, err := repos.NewTagLister(repos.SourceConfig{Type: repos.SourceGithub, URL: server.URL}, server.Client())
+	require.NoError(t, err)
+
+	t.Run("stops after the first stable release", func(t *testing.T) {
+		requested = nil
+
+		release, err := repos.LatestRelease(context.Background(), lister, "owner", "repo", repos.ReleaseOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "v1.1.0", release.Tag)
+		assert.Equal(t, []string{""}, requested)
+	})
+
+	t.Run("requests further pages until the constraint matches", func(t *testing.T) {
+		requested = nil
+
+		opts, err := repos.SourceConfig{Constraint: "<1.1"}.ReleaseOptions()
+		require.NoError(t, err)
+
+		release, err := repos.LatestRelease(context.Background(), lister, "owner", "repo", opts)
+		require.NoError(t, err)
+		assert.Equal(t, "v1.0.0", release.Tag)
+		assert.Equal(t, []string{"", "2"}, requested)
+	})
+}